@@ -0,0 +1,62 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/grysj/remitly-api/db"
+)
+
+// ISO 20022 limits institution names to 140 characters.
+const maxAutocompletePrefix = 140
+
+type autocompleteRes struct {
+	Prefix     string          `json:"prefix"`
+	SwiftCodes []db.Suggestion `json:"swiftCodes"`
+	BankNames  []db.Suggestion `json:"bankNames"`
+}
+
+// autocomplete suggests codes and bank names starting with prefix. It is
+// meant to be called on every keystroke, so it reads two sorted-set ranges
+// and the matched records, nothing else.
+func (server *Server) autocomplete(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	prefix := query.Get("prefix")
+	if len(prefix) > maxAutocompletePrefix {
+		http.Error(w, "Prefix is too long", http.StatusBadRequest)
+		return
+	}
+
+	limit, err := queryInt(query.Get("limit"), db.DefaultAutocompleteLimit)
+	if err != nil || limit < 1 || limit > db.MaxAutocompleteLimit {
+		http.Error(w, "Invalid limit", http.StatusBadRequest)
+		return
+	}
+
+	result, err := server.storeFor(r).Autocomplete(db.AutocompleteParams{
+		Prefix: prefix,
+		Limit:  limit,
+	})
+	if errors.Is(err, db.ErrEmptyPrefix) {
+		http.Error(w, "Missing prefix", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("Error autocompleting %q: %v", prefix, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(autocompleteRes{
+		Prefix:     prefix,
+		SwiftCodes: result.SwiftCodes,
+		BankNames:  result.BankNames,
+	}); err != nil {
+		log.Printf("Error encoding response: %v", err)
+		http.Error(w, "Error generating response", http.StatusInternalServerError)
+		return
+	}
+}
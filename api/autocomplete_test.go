@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grysj/remitly-api/db"
+	"github.com/grysj/remitly-api/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutocomplete(t *testing.T) {
+	require.NoError(t, testServer.store.CleanDB(testCtx))
+	defer testServer.store.CleanDB(testCtx)
+
+	_, err := testServer.store.ReloadDataset(db.ReloadDatasetParams{Rows: []parser.CsvRow{
+		{ISO2: "MT", Swift: "AKBKMTMTXXX", Name: "AKBANK T.A.S.", Country: "MALTA"},
+		{ISO2: "PL", Swift: "ALBPPLP1BMW", Name: "ALIOR BANK S.A.", Country: "POLAND"},
+	}})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		checkResponse  func(*testing.T, autocompleteRes)
+	}{
+		{
+			name:           "Code Prefix",
+			query:          "prefix=albp",
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, response autocompleteRes) {
+				assert.Equal(t, []db.Suggestion{
+					{Swift: "ALBPPLP1BMW", Name: "ALIOR BANK S.A.", ISO2: "PL"},
+				}, response.SwiftCodes)
+				assert.Empty(t, response.BankNames)
+			},
+		},
+		{
+			name:           "Name Prefix",
+			query:          "prefix=akbank%20t",
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, response autocompleteRes) {
+				assert.Empty(t, response.SwiftCodes)
+				assert.Equal(t, []db.Suggestion{
+					{Swift: "AKBKMTMTXXX", Name: "AKBANK T.A.S.", ISO2: "MT", Headquater: true},
+				}, response.BankNames)
+			},
+		},
+		{
+			name:           "Limit",
+			query:          "prefix=a&limit=1",
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, response autocompleteRes) {
+				assert.Len(t, response.SwiftCodes, 1)
+				assert.Len(t, response.BankNames, 1)
+			},
+		},
+		{
+			name:           "Missing Prefix",
+			query:          "",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid Limit",
+			query:          "prefix=a&limit=0",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/swift-codes/autocomplete?"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			testServer.router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.checkResponse == nil {
+				return
+			}
+			var response autocompleteRes
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+			tt.checkResponse(t, response)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/grysj/remitly-api/db"
+)
+
+const (
+	datasetVersionHeader = "X-Dataset-Version"
+	actorHeader          = "X-Actor"
+)
+
+type requestStoreKey struct{}
+
+type listDatasetsRes struct {
+	Active   string              `json:"active"`
+	Datasets []db.DatasetVersion `json:"datasets"`
+}
+
+// withRequestStore pins every request to the dataset version that is active
+// when it arrives and reports that version in the X-Dataset-Version header,
+// so a rollback in the middle of a request cannot mix two versions in one
+// response. Changes made by the request are attributed to its X-Actor.
+func (server *Server) withRequestStore(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		store := server.store.WithActor(requestActor(r))
+
+		version, err := server.store.ActiveDataset()
+		if err != nil {
+			log.Printf("Error reading active dataset version: %v", err)
+		} else if version != "" {
+			w.Header().Set(datasetVersionHeader, version)
+			store = store.AtDataset(version)
+		}
+
+		ctx := context.WithValue(r.Context(), requestStoreKey{}, store)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// requestActor names the author of the request's changes. The API has a
+// single shared password and no per-user identity, so X-Actor is asserted
+// by the client and only as trustworthy as whoever holds the password.
+func requestActor(r *http.Request) string {
+	if actor := strings.TrimSpace(r.Header.Get(actorHeader)); actor != "" {
+		return actor
+	}
+	return db.DefaultActor
+}
+
+// storeFor returns the store pinned to the request's dataset version and
+// acting on behalf of the request's actor.
+func (server *Server) storeFor(r *http.Request) *db.Store {
+	if store, ok := r.Context().Value(requestStoreKey{}).(*db.Store); ok {
+		return store
+	}
+	return server.store
+}
+
+func (server *Server) listDatasets(w http.ResponseWriter, r *http.Request) {
+	datasets, err := server.store.ListDatasets()
+	if err != nil {
+		log.Printf("Error listing dataset versions: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	response := listDatasetsRes{Datasets: datasets}
+	for _, dataset := range datasets {
+		if dataset.Active {
+			response.Active = dataset.ID
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding response: %v", err)
+		http.Error(w, "Error generating response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (server *Server) activateDataset(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	dataset, err := server.storeFor(r).ActivateDataset(id)
+	if errors.Is(err, db.ErrDatasetNotFound) {
+		http.Error(w, "Dataset version not found", http.StatusNotFound)
+		return
+	}
+	if err != nil && dataset != nil {
+		log.Printf("Warning: %v", err)
+	} else if err != nil {
+		log.Printf("Error activating dataset version %s: %v", id, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set(datasetVersionHeader, dataset.ID)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(dataset); err != nil {
+		log.Printf("Error encoding response: %v", err)
+		http.Error(w, "Error generating response", http.StatusInternalServerError)
+		return
+	}
+}
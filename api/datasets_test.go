@@ -0,0 +1,124 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grysj/remitly-api/db"
+	"github.com/grysj/remitly-api/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatasets(t *testing.T) {
+	require.NoError(t, testServer.store.CleanDB(testCtx))
+	defer testServer.store.CleanDB(testCtx)
+
+	for _, name := range []string{"AKBANK T.A.S.", "AKBANK"} {
+		_, err := testServer.store.ReloadDataset(db.ReloadDatasetParams{
+			Rows:   []parser.CsvRow{{Swift: "AKBKMTMTXXX", ISO2: "MT", Name: name, Country: "MALTA"}},
+			Source: "SWIFT_CODES.csv",
+		})
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		auth           bool
+		expectedStatus int
+		expectedHeader string
+		checkResponse  func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name:           "Lookup Reports Active Version",
+			method:         http.MethodGet,
+			path:           "/v1/swift-codes/AKBKMTMTXXX",
+			expectedStatus: http.StatusOK,
+			expectedHeader: "2",
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Contains(t, w.Body.String(), `"bankName":"AKBANK"`)
+			},
+		},
+		{
+			name:           "List Versions",
+			method:         http.MethodGet,
+			path:           "/v1/admin/datasets",
+			auth:           true,
+			expectedStatus: http.StatusOK,
+			expectedHeader: "2",
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				var response listDatasetsRes
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+				assert.Equal(t, "2", response.Active)
+				require.Len(t, response.Datasets, 2)
+				assert.Equal(t, "2", response.Datasets[0].ID)
+				assert.Equal(t, "SWIFT_CODES.csv", response.Datasets[0].Source)
+				assert.Equal(t, 1, response.Datasets[0].Rows)
+				assert.Equal(t, "1", response.Datasets[1].ID)
+			},
+		},
+		{
+			name:           "List Versions Without Password",
+			method:         http.MethodGet,
+			path:           "/v1/admin/datasets",
+			expectedStatus: http.StatusUnauthorized,
+			expectedHeader: "2",
+			checkResponse:  func(t *testing.T, w *httptest.ResponseRecorder) {},
+		},
+		{
+			name:           "Activate Unknown Version",
+			method:         http.MethodPost,
+			path:           "/v1/admin/datasets/42/activate",
+			auth:           true,
+			expectedStatus: http.StatusNotFound,
+			expectedHeader: "2",
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Contains(t, w.Body.String(), "Dataset version not found")
+			},
+		},
+		{
+			name:           "Roll Back",
+			method:         http.MethodPost,
+			path:           "/v1/admin/datasets/1/activate",
+			auth:           true,
+			expectedStatus: http.StatusOK,
+			expectedHeader: "1",
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				var response db.DatasetVersion
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+				assert.Equal(t, "1", response.ID)
+				assert.True(t, response.Active)
+			},
+		},
+		{
+			name:           "Lookup After Roll Back",
+			method:         http.MethodGet,
+			path:           "/v1/swift-codes/AKBKMTMTXXX",
+			expectedStatus: http.StatusOK,
+			expectedHeader: "1",
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Contains(t, w.Body.String(), `"bankName":"AKBANK T.A.S."`)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.auth {
+				req.Header.Set("Authorization", "Bearer "+password)
+			}
+			w := httptest.NewRecorder()
+
+			testServer.router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedHeader, w.Header().Get(datasetVersionHeader))
+			tt.checkResponse(t, w)
+		})
+	}
+}
@@ -2,11 +2,11 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
-	"strings"
 
+	"github.com/grysj/remitly-api/bic"
 	"github.com/grysj/remitly-api/db"
-	"github.com/grysj/remitly-api/util"
 )
 
 func (server *Server) deleteSwift(w http.ResponseWriter, r *http.Request) {
@@ -16,20 +16,27 @@ func (server *Server) deleteSwift(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var deleteErr error
-	if util.CheckIfHeadquater(swiftCode) {
-		prefix := strings.TrimSuffix(swiftCode, "XXX")
-		if len(prefix) != 8 {
-			http.Error(w, "Failed to delete bank", http.StatusBadRequest)
-			return
-		}
-		deleteErr = server.store.DeleteBanksBySwiftPrefix(prefix)
-	} else {
-		deleteErr = server.store.DeleteBankFromDB(db.DeleteBankParams{
-			Swift: swiftCode,
-		})
+	code, err := bic.Parse(swiftCode)
+	if err != nil {
+		http.Error(w, "Failed to delete bank", http.StatusBadRequest)
+		return
+	}
+
+	condition, ok := server.ifMatchCondition(w, r, code)
+	if !ok {
+		return
 	}
 
+	deleteErr := server.storeFor(r).DeleteBankFromDB(db.DeleteBankParams{
+		Swift:     code.BIC11(),
+		Revision:  condition.revision,
+		MustExist: condition.mustExist,
+		Cascade:   code.IsHeadquarters(),
+	})
+	if errors.Is(deleteErr, db.ErrRevisionMismatch) {
+		http.Error(w, "Precondition failed", http.StatusPreconditionFailed)
+		return
+	}
 	if deleteErr != nil {
 		http.Error(w, "Failed to delete bank", http.StatusInternalServerError)
 		return
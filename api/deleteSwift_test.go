@@ -37,7 +37,7 @@ func TestDeleteSwift(t *testing.T) {
 	}
 
 	for _, bank := range testBanks {
-		err := testServer.store.AddBankToDB(bank)
+		_, err := testServer.store.AddBankToDB(bank)
 		require.NoError(t, err)
 	}
 
@@ -83,3 +83,23 @@ func TestDeleteSwift(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteSwiftBIC8(t *testing.T) {
+	require.NoError(t, testServer.store.CleanDB(testCtx))
+	for _, swift := range []string{"BCHICLRMXXX", "BCHICLRM001"} {
+		_, err := testServer.store.AddBankToDB(db.Bank{Swift: swift, ISO2: "CL", Name: "BANCO DE CHILE", Country: "CHILE"})
+		require.NoError(t, err)
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "/v1/swift-codes/BCHICLRM", nil)
+	req.Header.Set("Authorization", "Bearer "+password)
+	w := httptest.NewRecorder()
+	testServer.router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	for _, swift := range []string{"BCHICLRMXXX", "BCHICLRM001"} {
+		bank, err := testServer.store.GetBankFromSwift(swift)
+		require.NoError(t, err)
+		assert.Nil(t, bank, swift)
+	}
+}
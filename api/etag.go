@@ -0,0 +1,103 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/grysj/remitly-api/bic"
+	"github.com/grysj/remitly-api/db"
+)
+
+func etag(revision int64) string {
+	return strconv.Quote(strconv.FormatInt(revision, 10))
+}
+
+func setETag(w http.ResponseWriter, revision int64) {
+	if revision > 0 {
+		w.Header().Set("ETag", etag(revision))
+	}
+}
+
+// ifMatch is the If-Match precondition of a request: "*" or a list of
+// entity-tags. Weak and malformed tags are kept out of revisions, so they
+// never match.
+type ifMatch struct {
+	present   bool
+	any       bool
+	revisions []int64
+}
+
+func parseIfMatch(r *http.Request) ifMatch {
+	var precondition ifMatch
+	for _, value := range r.Header.Values("If-Match") {
+		for _, tag := range strings.Split(value, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "" {
+				continue
+			}
+			precondition.present = true
+			if tag == "*" {
+				precondition.any = true
+				continue
+			}
+			unquoted, err := strconv.Unquote(tag)
+			if err != nil {
+				continue
+			}
+			revision, err := strconv.ParseInt(unquoted, 10, 64)
+			if err == nil && revision > 0 {
+				precondition.revisions = append(precondition.revisions, revision)
+			}
+		}
+	}
+	return precondition
+}
+
+// writeCondition is what a write must still find in the store, so the
+// If-Match check holds until the record is written. The zero value makes
+// the write unconditional.
+type writeCondition struct {
+	revision  int64
+	mustExist bool
+}
+
+// condition checks the precondition against the stored record and returns
+// the condition the write is made under. A list of tags pins the matched
+// revision, and "*" only requires the record to still exist.
+func (m ifMatch) condition(current *db.Bank) (writeCondition, bool) {
+	switch {
+	case !m.present:
+		return writeCondition{}, true
+	case current == nil:
+		return writeCondition{}, false
+	case m.any:
+		return writeCondition{mustExist: true}, true
+	case slices.Contains(m.revisions, current.Revision):
+		return writeCondition{revision: current.Revision}, true
+	}
+	return writeCondition{}, false
+}
+
+// ifMatchCondition evaluates the If-Match header of a write to the bank
+// identified by code. It writes the error response and returns false when
+// the precondition fails.
+func (server *Server) ifMatchCondition(w http.ResponseWriter, r *http.Request, code bic.BIC) (writeCondition, bool) {
+	precondition := parseIfMatch(r)
+	if !precondition.present {
+		return writeCondition{}, true
+	}
+	current, err := server.storeFor(r).GetBank(code.BIC11())
+	if err != nil {
+		log.Printf("Error retrieving bank details: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return writeCondition{}, false
+	}
+	condition, ok := precondition.condition(current)
+	if !ok {
+		http.Error(w, "Precondition failed", http.StatusPreconditionFailed)
+	}
+	return condition, ok
+}
@@ -0,0 +1,253 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/grysj/remitly-api/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConditionalWrites(t *testing.T) {
+	original := db.Bank{
+		Swift:    "AKBKMTMTXXX",
+		ISO2:     "MT",
+		Name:     "AKBANK T.A.S.",
+		Type:     "BIC11",
+		Address:  "PORTOMASO BUSINESS TOWER",
+		Town:     "ST. JULIAN'S",
+		Country:  "MALTA",
+		Timezone: "Europe/Malta",
+	}
+
+	// "current" is replaced with the ETag returned by GET
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		header         string
+		value          string
+		expectedStatus int
+		wantName       string
+		wantDeleted    bool
+	}{
+		{
+			name:           "Put With Current ETag",
+			method:         http.MethodPut,
+			path:           "/v1/swift-codes/AKBKMTMTXXX",
+			body:           `{"bankName":"Akbank","countryISO2":"MT"}`,
+			header:         "If-Match",
+			value:          "current",
+			expectedStatus: http.StatusOK,
+			wantName:       "AKBANK",
+		},
+		{
+			name:           "Put With Stale ETag",
+			method:         http.MethodPut,
+			path:           "/v1/swift-codes/AKBKMTMTXXX",
+			body:           `{"bankName":"Akbank","countryISO2":"MT"}`,
+			header:         "If-Match",
+			value:          `"123456"`,
+			expectedStatus: http.StatusPreconditionFailed,
+			wantName:       "AKBANK T.A.S.",
+		},
+		{
+			name:           "Put With Any ETag",
+			method:         http.MethodPut,
+			path:           "/v1/swift-codes/AKBKMTMTXXX",
+			body:           `{"bankName":"Akbank","countryISO2":"MT"}`,
+			header:         "If-Match",
+			value:          "*",
+			expectedStatus: http.StatusOK,
+			wantName:       "AKBANK",
+		},
+		{
+			name:           "Put With Weak ETag",
+			method:         http.MethodPut,
+			path:           "/v1/swift-codes/AKBKMTMTXXX",
+			body:           `{"bankName":"Akbank","countryISO2":"MT"}`,
+			header:         "If-Match",
+			value:          "W/current",
+			expectedStatus: http.StatusPreconditionFailed,
+			wantName:       "AKBANK T.A.S.",
+		},
+		{
+			name:           "Put With ETag List",
+			method:         http.MethodPut,
+			path:           "/v1/swift-codes/AKBKMTMTXXX",
+			body:           `{"bankName":"Akbank","countryISO2":"MT"}`,
+			header:         "If-Match",
+			value:          `"123456", current`,
+			expectedStatus: http.StatusOK,
+			wantName:       "AKBANK",
+		},
+		{
+			name:           "Put Unknown Code With Any ETag",
+			method:         http.MethodPut,
+			path:           "/v1/swift-codes/ALBPPLP1BMW",
+			body:           `{"bankName":"Alior Bank","countryISO2":"PL"}`,
+			header:         "If-Match",
+			value:          "*",
+			expectedStatus: http.StatusPreconditionFailed,
+			wantName:       "AKBANK T.A.S.",
+		},
+		{
+			name:           "Patch With Current ETag",
+			method:         http.MethodPatch,
+			path:           "/v1/swift-codes/AKBKMTMTXXX",
+			body:           `{"bankName":"Akbank"}`,
+			header:         "If-Match",
+			value:          "current",
+			expectedStatus: http.StatusOK,
+			wantName:       "AKBANK",
+		},
+		{
+			name:           "Patch With Stale ETag",
+			method:         http.MethodPatch,
+			path:           "/v1/swift-codes/AKBKMTMTXXX",
+			body:           `{"bankName":"Akbank"}`,
+			header:         "If-Match",
+			value:          `"123456"`,
+			expectedStatus: http.StatusPreconditionFailed,
+			wantName:       "AKBANK T.A.S.",
+		},
+		{
+			name:           "Delete With Current ETag",
+			method:         http.MethodDelete,
+			path:           "/v1/swift-codes/AKBKMTMTXXX",
+			header:         "If-Match",
+			value:          "current",
+			expectedStatus: http.StatusOK,
+			wantDeleted:    true,
+		},
+		{
+			name:           "Delete With Stale ETag",
+			method:         http.MethodDelete,
+			path:           "/v1/swift-codes/AKBKMTMTXXX",
+			header:         "If-Match",
+			value:          `"123456"`,
+			expectedStatus: http.StatusPreconditionFailed,
+			wantName:       "AKBANK T.A.S.",
+		},
+		{
+			name:           "Delete With Stale ETag List",
+			method:         http.MethodDelete,
+			path:           "/v1/swift-codes/AKBKMTMTXXX",
+			header:         "If-Match",
+			value:          `"123456", "123457"`,
+			expectedStatus: http.StatusPreconditionFailed,
+			wantName:       "AKBANK T.A.S.",
+		},
+		{
+			name:           "Delete Unknown Code With Any ETag",
+			method:         http.MethodDelete,
+			path:           "/v1/swift-codes/ALBPPLP1BMW",
+			header:         "If-Match",
+			value:          "*",
+			expectedStatus: http.StatusPreconditionFailed,
+			wantName:       "AKBANK T.A.S.",
+		},
+		{
+			name:           "Delete With Any ETag",
+			method:         http.MethodDelete,
+			path:           "/v1/swift-codes/AKBKMTMTXXX",
+			header:         "If-Match",
+			value:          "*",
+			expectedStatus: http.StatusOK,
+			wantDeleted:    true,
+		},
+		{
+			name:           "Post Returns ETag",
+			method:         http.MethodPost,
+			path:           "/v1/swift-codes",
+			body:           `{"swiftCode":"AKBKMTMT001","bankName":"Akbank Branch","countryISO2":"MT"}`,
+			header:         "If-None-Match",
+			value:          "*",
+			expectedStatus: http.StatusCreated,
+			wantName:       "AKBANK T.A.S.",
+		},
+		{
+			name:           "Create Only Post Of Existing Code",
+			method:         http.MethodPost,
+			path:           "/v1/swift-codes",
+			body:           `{"swiftCode":"AKBKMTMTXXX","bankName":"Akbank","countryISO2":"MT"}`,
+			header:         "If-None-Match",
+			value:          "*",
+			expectedStatus: http.StatusPreconditionFailed,
+			wantName:       "AKBANK T.A.S.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, testServer.store.CleanDB(testCtx))
+			_, err := testServer.store.AddBankToDB(original)
+			require.NoError(t, err)
+
+			get := httptest.NewRecorder()
+			testServer.router.ServeHTTP(get, httptest.NewRequest(http.MethodGet, "/v1/swift-codes/AKBKMTMTXXX", nil))
+			require.Equal(t, http.StatusOK, get.Code)
+			current := get.Header().Get("ETag")
+			require.NotEmpty(t, current)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer "+password)
+			req.Header.Set(tt.header, strings.Replace(tt.value, "current", current, 1))
+			w := httptest.NewRecorder()
+
+			testServer.router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if (tt.expectedStatus == http.StatusOK || tt.expectedStatus == http.StatusCreated) && tt.method != http.MethodDelete {
+				assert.NotEmpty(t, w.Header().Get("ETag"))
+				assert.NotEqual(t, current, w.Header().Get("ETag"))
+			}
+
+			bank, err := testServer.store.GetBank("AKBKMTMTXXX")
+			require.NoError(t, err)
+			if tt.wantDeleted {
+				assert.Nil(t, bank)
+				return
+			}
+			require.NotNil(t, bank)
+			assert.Equal(t, tt.wantName, bank.Name)
+		})
+	}
+	testServer.store.CleanDB(testCtx)
+}
+
+func TestIfMatchCondition(t *testing.T) {
+	stored := &db.Bank{Swift: "AKBKMTMTXXX", Revision: 7}
+
+	tests := []struct {
+		name          string
+		value         string
+		current       *db.Bank
+		wantOK        bool
+		wantCondition writeCondition
+	}{
+		{name: "No Header", current: stored, wantOK: true},
+		{name: "Any Tag", value: "*", current: stored, wantOK: true, wantCondition: writeCondition{mustExist: true}},
+		{name: "Any Tag Of Missing Record", value: "*", wantOK: false},
+		{name: "Matching Tag", value: `"3", "7"`, current: stored, wantOK: true, wantCondition: writeCondition{revision: 7}},
+		{name: "Stale Tag", value: `"3"`, current: stored, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/v1/swift-codes/AKBKMTMTXXX", nil)
+			if tt.value != "" {
+				req.Header.Set("If-Match", tt.value)
+			}
+
+			condition, ok := parseIfMatch(req).condition(tt.current)
+
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantCondition, condition)
+		})
+	}
+}
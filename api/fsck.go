@@ -0,0 +1,29 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/grysj/remitly-api/db"
+)
+
+// checkConsistency reports index entries that disagree with the stored
+// banks. POST rebuilds the indexes of the active dataset version as well.
+func (server *Server) checkConsistency(w http.ResponseWriter, r *http.Request) {
+	report, err := server.storeFor(r).CheckConsistency(db.CheckConsistencyParams{
+		Repair: r.Method == http.MethodPost,
+	})
+	if err != nil {
+		log.Printf("Error checking store consistency: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		log.Printf("Error encoding response: %v", err)
+		http.Error(w, "Error generating response", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grysj/remitly-api/db"
+	"github.com/grysj/remitly-api/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckConsistency(t *testing.T) {
+	require.NoError(t, testServer.store.CleanDB(testCtx))
+	defer testServer.store.CleanDB(testCtx)
+
+	// a branch whose headquarters is not in the dataset
+	_, err := testServer.store.ReloadDataset(db.ReloadDatasetParams{
+		Rows: []parser.CsvRow{
+			{Swift: "AKBKMTMTXXX", ISO2: "MT", Name: "AKBANK T.A.S.", Country: "MALTA"},
+			{Swift: "ALBPPLP1BMW", ISO2: "PL", Name: "ALIOR BANK S.A.", Country: "POLAND"},
+		},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		method         string
+		auth           bool
+		expectedStatus int
+		wantRepaired   bool
+	}{
+		{
+			name:           "Check Without Authorization",
+			method:         http.MethodGet,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Check",
+			method:         http.MethodGet,
+			auth:           true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Repair",
+			method:         http.MethodPost,
+			auth:           true,
+			expectedStatus: http.StatusOK,
+			wantRepaired:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/v1/admin/fsck", nil)
+			if tt.auth {
+				req.Header.Set("Authorization", "Bearer "+password)
+			}
+			w := httptest.NewRecorder()
+
+			testServer.router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if w.Code != http.StatusOK {
+				return
+			}
+			var report db.ConsistencyReport
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&report))
+			assert.Equal(t, 2, report.Banks)
+			assert.Equal(t, tt.wantRepaired, report.Repaired)
+			assert.Equal(t, []db.ConsistencyIssue{
+				{Kind: db.IssueOrphanBranchSet, Key: "ds:1:branch:ALBPPLP1"},
+			}, report.Issues)
+		})
+	}
+}
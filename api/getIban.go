@@ -0,0 +1,64 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/grysj/remitly-api/iban"
+)
+
+type getIbanRes struct {
+	Iban        string              `json:"iban"`
+	Valid       bool                `json:"valid"`
+	Error       string              `json:"error,omitempty"`
+	CountryISO2 string              `json:"countryISO2,omitempty"`
+	CheckDigits string              `json:"checkDigits,omitempty"`
+	Bban        string              `json:"bban,omitempty"`
+	BankCode    string              `json:"bankCode,omitempty"`
+	Swift       string              `json:"swiftCode,omitempty"`
+	Bank        *getSwiftDetailsRes `json:"bank,omitempty"`
+}
+
+func (server *Server) getIban(w http.ResponseWriter, r *http.Request) {
+	value := r.PathValue("iban")
+	if value == "" {
+		http.Error(w, "Missing IBAN", http.StatusBadRequest)
+		return
+	}
+
+	response := getIbanRes{Iban: value}
+	code, err := iban.Parse(value)
+	if err != nil {
+		response.Error = err.Error()
+	} else {
+		response = getIbanRes{
+			Iban:        code.String(),
+			Valid:       true,
+			CountryISO2: code.Country,
+			CheckDigits: code.CheckDigits,
+			Bban:        code.BBAN,
+			BankCode:    code.BankCode,
+		}
+
+		if swift, ok := server.bankCodes.Lookup(code); ok {
+			response.Swift = swift
+			bank, err := server.storeFor(r).GetBankFromSwift(swift)
+			if err != nil {
+				log.Printf("Error retrieving bank details: %v", err)
+				http.Error(w, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+			if bank != nil {
+				details := newSwiftDetailsRes(bank)
+				response.Bank = &details
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
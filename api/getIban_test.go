@@ -0,0 +1,107 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/grysj/remitly-api/db"
+	"github.com/grysj/remitly-api/iban"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetIban(t *testing.T) {
+	require.NoError(t, testServer.store.CleanDB(testCtx))
+
+	_, err := testServer.store.AddBankToDB(db.Bank{
+		Swift:    "WBKPPLPPXXX",
+		ISO2:     "PL",
+		Name:     "SANTANDER BANK POLSKA S.A.",
+		Address:  "AL. JANA PAWLA II 17, WARSZAWA, 00-854",
+		Town:     "WARSZAWA",
+		Country:  "POLAND",
+		Timezone: "Europe/Warsaw",
+	})
+	require.NoError(t, err)
+
+	bankCodes := testServer.bankCodes
+	testServer.bankCodes = iban.BankCodes{
+		"PL": {
+			"10901014": "WBKPPLPPXXX",
+			"10201026": "BPKOPLPWXXX",
+		},
+	}
+	defer func() { testServer.bankCodes = bankCodes }()
+
+	tests := []struct {
+		name           string
+		iban           string
+		expectedStatus int
+		checkResponse  func(*testing.T, getIbanRes)
+	}{
+		{
+			name:           "Valid IBAN With Known Bank",
+			iban:           "PL61 1090 1014 0000 0712 1981 2874",
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, res getIbanRes) {
+				assert.True(t, res.Valid)
+				assert.Equal(t, "PL61109010140000071219812874", res.Iban)
+				assert.Equal(t, "PL", res.CountryISO2)
+				assert.Equal(t, "10901014", res.BankCode)
+				assert.Equal(t, "WBKPPLPPXXX", res.Swift)
+				require.NotNil(t, res.Bank)
+				assert.Equal(t, "SANTANDER BANK POLSKA S.A.", res.Bank.BankName)
+				assert.True(t, res.Bank.Headquater)
+			},
+		},
+		{
+			name:           "Mapped Bank Missing From Store",
+			iban:           "PL60102010260000042270201111",
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, res getIbanRes) {
+				assert.True(t, res.Valid)
+				assert.Equal(t, "BPKOPLPWXXX", res.Swift)
+				assert.Nil(t, res.Bank)
+			},
+		},
+		{
+			name:           "Unmapped Bank Code",
+			iban:           "DE89370400440532013000",
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, res getIbanRes) {
+				assert.True(t, res.Valid)
+				assert.Equal(t, "37040044", res.BankCode)
+				assert.Empty(t, res.Swift)
+				assert.Nil(t, res.Bank)
+			},
+		},
+		{
+			name:           "Invalid Checksum",
+			iban:           "PL62109010140000071219812874",
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, res getIbanRes) {
+				assert.False(t, res.Valid)
+				assert.Contains(t, res.Error, "check digits")
+				assert.Empty(t, res.Swift)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/iban/"+url.PathEscape(tt.iban), nil)
+			w := httptest.NewRecorder()
+
+			testServer.router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			var res getIbanRes
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&res))
+			tt.checkResponse(t, res)
+		})
+	}
+	require.NoError(t, testServer.store.CleanDB(testCtx))
+}
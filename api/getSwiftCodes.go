@@ -39,7 +39,7 @@ func (server *Server) getSwiftCodes(w http.ResponseWriter, r *http.Request) {
 		SwiftCodes:  make([]BankInfo, 0),
 	}
 
-	countryName, err := server.store.GetCountryNameByISO2(countryCode)
+	countryName, err := server.storeFor(r).GetCountryNameByISO2(countryCode)
 	if err != nil {
 		log.Printf("Error retrieving country name for %s: %v", countryCode, err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -48,7 +48,7 @@ func (server *Server) getSwiftCodes(w http.ResponseWriter, r *http.Request) {
 
 	response.CountryName = countryName
 
-	banks, err := server.store.GetBanksByISO2(countryCode)
+	banks, err := server.storeFor(r).GetBanksByISO2(countryCode)
 	if err != nil {
 		log.Printf("Error retrieving banks for country %s: %v", countryCode, err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -24,7 +24,7 @@ func TestGetSwiftCodes(t *testing.T) {
 		Timezone:   "Pacific/Easter",
 		Headquater: true,
 	}
-	err := testServer.store.AddBankToDB(chileHQ)
+	_, err := testServer.store.AddBankToDB(chileHQ)
 	require.NoError(t, err)
 
 	chileBranches := []db.Bank{
@@ -53,7 +53,7 @@ func TestGetSwiftCodes(t *testing.T) {
 	}
 
 	for _, branch := range chileBranches {
-		err := testServer.store.AddBankToDB(branch)
+		_, err := testServer.store.AddBankToDB(branch)
 		require.NoError(t, err)
 	}
 
@@ -83,7 +83,7 @@ func TestGetSwiftCodes(t *testing.T) {
 	}
 
 	for _, bank := range monacoBanks {
-		err := testServer.store.AddBankToDB(bank)
+		_, err := testServer.store.AddBankToDB(bank)
 		require.NoError(t, err)
 	}
 
@@ -4,25 +4,33 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"strings"
 
+	"github.com/grysj/remitly-api/bic"
 	"github.com/grysj/remitly-api/db"
-	"github.com/grysj/remitly-api/util"
 )
 
 func (server *Server) getSwiftDetails(w http.ResponseWriter, r *http.Request) {
 	swiftCode := r.PathValue("swiftcode")
 
+	// a {swiftcode}/history pattern would conflict with the country route
+	if swift, ok := strings.CutSuffix(swiftCode, "/history"); ok {
+		server.getSwiftHistory(w, r, swift)
+		return
+	}
+
 	if swiftCode == "" {
 		http.Error(w, "Missing Swift code", http.StatusBadRequest)
 		return
 	}
 
-	if len(swiftCode) != 11 {
+	code, err := bic.Parse(swiftCode)
+	if err != nil {
 		http.Error(w, "Invalid Swift code format", http.StatusBadRequest)
 		return
 	}
 
-	bank, err := server.store.GetBankFromSwift(swiftCode)
+	bank, err := server.storeFor(r).GetBankFromSwift(code.BIC11())
 	if err != nil {
 		log.Printf("Error retrieving bank details: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -32,25 +40,19 @@ func (server *Server) getSwiftDetails(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Bank not found", http.StatusNotFound)
 		return
 	}
-	var response getSwiftDetailsRes
-
-	response = getSwiftDetailsRes{
-		Address:     bank.Address,
-		BankName:    bank.Name,
-		CountryISO2: bank.ISO2,
-		CountryName: bank.Country,
-		Headquater:  bank.Headquater,
-		Swift:       bank.Swift,
-	}
+	response := newSwiftDetailsRes(bank)
+	response.RequestedSwift = code.String()
+	response.RequestedFormat = code.Format()
 
-	if util.CheckIfHeadquater(swiftCode) {
-		branches, err := server.store.GetBankBranches(swiftCode)
+	if code.IsHeadquarters() {
+		branches, err := server.storeFor(r).GetBankBranches(code.BIC11())
 		if err != nil {
 			log.Printf("Error retrieving bank branches: %v", err)
 		}
 		response.Branches = branches
 	}
 
+	setETag(w, bank.Revision)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 
@@ -62,12 +64,39 @@ func (server *Server) getSwiftDetails(w http.ResponseWriter, r *http.Request) {
 
 }
 
+func newSwiftDetailsRes(bank *db.GetBankBySwiftResult) getSwiftDetailsRes {
+	return getSwiftDetailsRes{
+		Address:     bank.Address,
+		BankName:    bank.Name,
+		CountryISO2: bank.ISO2,
+		CountryName: bank.Country,
+		Headquater:  bank.Headquater,
+		Swift:       bank.Swift,
+		StructuredAddress: structuredAddressRes{
+			StreetLines: db.StreetLines(bank.Street),
+			Postcode:    bank.Postcode,
+			TownName:    bank.Town,
+			Region:      bank.Region,
+		},
+	}
+}
+
+type structuredAddressRes struct {
+	StreetLines []string `json:"streetLines"`
+	Postcode    string   `json:"postcode"`
+	TownName    string   `json:"townName"`
+	Region      string   `json:"region,omitempty"`
+}
+
 type getSwiftDetailsRes struct {
-	Address     string                        `json:"address"`
-	BankName    string                        `json:"bankName,omitempty"`
-	CountryISO2 string                        `json:"countryISO2"`
-	CountryName string                        `json:"countryName"`
-	Headquater  bool                          `json:"isHeadquater"`
-	Swift       string                        `json:"swiftCode"`
-	Branches    []db.GetBranchesBySwiftResult `json:"branches,omitempty"`
+	Address           string                        `json:"address"`
+	StructuredAddress structuredAddressRes          `json:"structuredAddress"`
+	BankName          string                        `json:"bankName,omitempty"`
+	CountryISO2       string                        `json:"countryISO2"`
+	CountryName       string                        `json:"countryName"`
+	Headquater        bool                          `json:"isHeadquater"`
+	Swift             string                        `json:"swiftCode"`
+	RequestedSwift    string                        `json:"requestedSwiftCode,omitempty"`
+	RequestedFormat   string                        `json:"requestedFormat,omitempty"`
+	Branches          []db.GetBranchesBySwiftResult `json:"branches,omitempty"`
 }
@@ -24,7 +24,7 @@ func TestGetSwiftDetails(t *testing.T) {
 		Timezone:   "Europe/Malta",
 		Headquater: true,
 	}
-	err := testServer.store.AddBankToDB(testHQ)
+	_, err := testServer.store.AddBankToDB(testHQ)
 	require.NoError(t, err)
 
 	testBranch := db.Bank{
@@ -38,7 +38,7 @@ func TestGetSwiftDetails(t *testing.T) {
 		Timezone:   "Europe/Warsaw",
 		Headquater: false,
 	}
-	err = testServer.store.AddBankToDB(testBranch)
+	_, err = testServer.store.AddBankToDB(testBranch)
 	require.NoError(t, err)
 
 	tests := []struct {
@@ -62,6 +62,28 @@ func TestGetSwiftDetails(t *testing.T) {
 				assert.Equal(t, "MALTA", response.CountryName)
 				assert.True(t, response.Headquater, "Should be headquarters due to XXX suffix")
 				assert.Equal(t, "AKBKMTMTXXX", response.Swift)
+				assert.Equal(t, "AKBKMTMTXXX", response.RequestedSwift)
+				assert.Equal(t, "BIC11", response.RequestedFormat)
+				assert.Equal(t, structuredAddressRes{
+					StreetLines: []string{"PORTOMASO BUSINESS TOWER"},
+					TownName:    "ST. JULIAN'S",
+				}, response.StructuredAddress)
+			},
+		},
+		{
+			name:           "Eight-Character Code Resolves To Headquarters",
+			swiftCode:      "akbkmtmt",
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				var response getSwiftDetailsRes
+				err := json.NewDecoder(w.Body).Decode(&response)
+				require.NoError(t, err)
+
+				assert.Equal(t, "AKBKMTMTXXX", response.Swift)
+				assert.Equal(t, "AKBKMTMT", response.RequestedSwift)
+				assert.Equal(t, "BIC8", response.RequestedFormat)
+				assert.True(t, response.Headquater)
+				assert.Equal(t, "AKBANK T.A.S.", response.BankName)
 			},
 		},
 		{
@@ -80,6 +102,11 @@ func TestGetSwiftDetails(t *testing.T) {
 				assert.False(t, response.Headquater, "Should not be headquarters as suffix is not XXX")
 				assert.Equal(t, "ALBPPLP1BMW", response.Swift)
 				assert.Nil(t, response.Branches, "Branch should not have sub-branches")
+				assert.Equal(t, structuredAddressRes{
+					StreetLines: []string{},
+					TownName:    "WARSZAWA",
+					Region:      "MAZOWIECKIE",
+				}, response.StructuredAddress)
 			},
 		},
 		{
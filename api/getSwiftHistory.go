@@ -0,0 +1,41 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/grysj/remitly-api/bic"
+	"github.com/grysj/remitly-api/db"
+)
+
+type getSwiftHistoryRes struct {
+	Swift  string            `json:"swiftCode"`
+	Events []db.HistoryEvent `json:"events"`
+}
+
+func (server *Server) getSwiftHistory(w http.ResponseWriter, r *http.Request, swiftCode string) {
+	code, err := bic.Parse(swiftCode)
+	if err != nil {
+		http.Error(w, "Invalid Swift code format", http.StatusBadRequest)
+		return
+	}
+
+	events, err := server.storeFor(r).GetBankHistory(code.BIC11())
+	if err != nil {
+		log.Printf("Error retrieving history: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if len(events) == 0 {
+		http.Error(w, "No history found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(getSwiftHistoryRes{Swift: code.BIC11(), Events: events}); err != nil {
+		log.Printf("Error encoding response: %v", err)
+		http.Error(w, "Error generating response", http.StatusInternalServerError)
+		return
+	}
+}
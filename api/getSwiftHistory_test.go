@@ -0,0 +1,107 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/grysj/remitly-api/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSwiftHistory(t *testing.T) {
+	require.NoError(t, testServer.store.CleanDB(testCtx))
+	defer testServer.store.CleanDB(testCtx)
+
+	requests := []struct {
+		method string
+		path   string
+		body   string
+		actor  string
+		status int
+	}{
+		{http.MethodPost, "/v1/swift-codes", `{"swiftCode":"AKBKMTMTXXX","bankName":"AKBANK T.A.S.","countryISO2":"MT","countryName":"MALTA"}`, "alice", http.StatusCreated},
+		{http.MethodPatch, "/v1/swift-codes/AKBKMTMTXXX", `{"bankName":"AKBANK"}`, "", http.StatusOK},
+		{http.MethodDelete, "/v1/swift-codes/AKBKMTMTXXX", "", "bob", http.StatusOK},
+	}
+	for _, req := range requests {
+		r := httptest.NewRequest(req.method, req.path, strings.NewReader(req.body))
+		r.Header.Set("Authorization", "Bearer "+password)
+		if req.actor != "" {
+			r.Header.Set("X-Actor", req.actor)
+		}
+		w := httptest.NewRecorder()
+		testServer.router.ServeHTTP(w, r)
+		require.Equal(t, req.status, w.Code, w.Body.String())
+	}
+
+	tests := []struct {
+		name           string
+		swiftCode      string
+		expectedStatus int
+		checkResponse  func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name:           "Deleted Code",
+			swiftCode:      "AKBKMTMTXXX",
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				var response getSwiftHistoryRes
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+				assert.Equal(t, "AKBKMTMTXXX", response.Swift)
+				require.Len(t, response.Events, 3)
+
+				assert.Equal(t, db.ChangeCreate, response.Events[0].Action)
+				assert.Equal(t, "alice", response.Events[0].Actor)
+				assert.Equal(t, db.SourceAPI, response.Events[0].Source)
+
+				assert.Equal(t, db.ChangeUpdate, response.Events[1].Action)
+				assert.Equal(t, db.DefaultActor, response.Events[1].Actor)
+				assert.Equal(t, []db.FieldChange{{Field: "bankName", Before: "AKBANK T.A.S.", After: "AKBANK"}}, response.Events[1].Changes)
+
+				assert.Equal(t, db.ChangeDelete, response.Events[2].Action)
+				assert.Equal(t, "bob", response.Events[2].Actor)
+				assert.Equal(t, "AKBANK", response.Events[2].Old.Name)
+			},
+		},
+		{
+			name:           "Eight-Character Code",
+			swiftCode:      "akbkmtmt",
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Contains(t, w.Body.String(), `"swiftCode":"AKBKMTMTXXX"`)
+			},
+		},
+		{
+			name:           "Never Stored",
+			swiftCode:      "ALBPPLP1BMW",
+			expectedStatus: http.StatusNotFound,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Contains(t, w.Body.String(), "No history found")
+			},
+		},
+		{
+			name:           "Invalid Code",
+			swiftCode:      "TESTXX",
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Contains(t, w.Body.String(), "Invalid Swift code format")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/swift-codes/"+tt.swiftCode+"/history", nil)
+			w := httptest.NewRecorder()
+
+			testServer.router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			tt.checkResponse(t, w)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/grysj/remitly-api/db"
+)
+
+type getTownsRes struct {
+	CountryISO2 string         `json:"countryISO2"`
+	CountryName string         `json:"countryName"`
+	Towns       []db.TownCount `json:"towns"`
+}
+
+type getTownSwiftCodesRes struct {
+	CountryISO2 string     `json:"countryISO2"`
+	CountryName string     `json:"countryName"`
+	TownName    string     `json:"townName"`
+	SwiftCodes  []BankInfo `json:"swiftCodes"`
+}
+
+func (server *Server) getTowns(w http.ResponseWriter, r *http.Request) {
+	countryCode := strings.ToUpper(r.PathValue("countryISO2code"))
+	if len(countryCode) != 2 {
+		http.Error(w, "Invalid country code format", http.StatusBadRequest)
+		return
+	}
+
+	countryName, err := server.storeFor(r).GetCountryNameByISO2(countryCode)
+	if err != nil {
+		log.Printf("Error retrieving country name for %s: %v", countryCode, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	towns, err := server.storeFor(r).GetTownsByISO2(countryCode)
+	if err != nil {
+		log.Printf("Error retrieving towns for country %s: %v", countryCode, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(getTownsRes{
+		CountryISO2: countryCode,
+		CountryName: countryName,
+		Towns:       towns,
+	}); err != nil {
+		log.Printf("Error encoding response: %v", err)
+		http.Error(w, "Error generating response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// getTownSwiftCodes lists the codes of one town. The town is matched
+// ignoring case, accents and punctuation, so /towns/krakow finds KRAKÓW.
+func (server *Server) getTownSwiftCodes(w http.ResponseWriter, r *http.Request) {
+	countryCode := strings.ToUpper(r.PathValue("countryISO2code"))
+	if len(countryCode) != 2 {
+		http.Error(w, "Invalid country code format", http.StatusBadRequest)
+		return
+	}
+	town := r.PathValue("town")
+
+	banks, err := server.storeFor(r).GetBanksByTown(countryCode, town)
+	if err != nil {
+		log.Printf("Error retrieving banks in %s, %s: %v", town, countryCode, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if len(banks) == 0 {
+		http.Error(w, "Town not found", http.StatusNotFound)
+		return
+	}
+
+	countryName, err := server.storeFor(r).GetCountryNameByISO2(countryCode)
+	if err != nil {
+		log.Printf("Error retrieving country name for %s: %v", countryCode, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	response := getTownSwiftCodesRes{
+		CountryISO2: countryCode,
+		CountryName: countryName,
+		TownName:    town,
+		SwiftCodes:  make([]BankInfo, 0, len(banks)),
+	}
+	for _, bank := range banks {
+		response.SwiftCodes = append(response.SwiftCodes, BankInfo{
+			Address:      bank.Address,
+			BankName:     bank.Name,
+			CountryISO2:  bank.ISO2,
+			IsHeadquater: bank.Headquater,
+			SwiftCode:    bank.Swift,
+		})
+	}
+	sort.Slice(response.SwiftCodes, func(i, j int) bool {
+		return response.SwiftCodes[i].SwiftCode < response.SwiftCodes[j].SwiftCode
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding response: %v", err)
+		http.Error(w, "Error generating response", http.StatusInternalServerError)
+		return
+	}
+}
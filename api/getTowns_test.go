@@ -0,0 +1,93 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grysj/remitly-api/db"
+	"github.com/grysj/remitly-api/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTowns(t *testing.T) {
+	require.NoError(t, testServer.store.CleanDB(testCtx))
+	defer testServer.store.CleanDB(testCtx)
+
+	_, err := testServer.store.ReloadDataset(db.ReloadDatasetParams{Rows: []parser.CsvRow{
+		{ISO2: "PL", Swift: "CITIPLPXKRK", Name: "BANK HANDLOWY W WARSZAWIE S.A.", Town: "KRAKÓW", Country: "POLAND"},
+		{ISO2: "PL", Swift: "PKOPPLPWKRK", Name: "BANK PEKAO S.A.", Town: "KRAKOW", Country: "POLAND"},
+		{ISO2: "PL", Swift: "BPKOPLPWXXX", Name: "PKO BANK POLSKI S.A.", Town: "WARSZAWA", Country: "POLAND"},
+	}})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		checkResponse  func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name:           "List Towns",
+			path:           "/v1/swift-codes/country/pl/towns",
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				var response getTownsRes
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+				assert.Equal(t, "PL", response.CountryISO2)
+				assert.Equal(t, "POLAND", response.CountryName)
+				assert.Equal(t, []db.TownCount{
+					{Name: "KRAKÓW", Count: 2},
+					{Name: "WARSZAWA", Count: 1},
+				}, response.Towns)
+			},
+		},
+		{
+			name:           "Banks In Town",
+			path:           "/v1/swift-codes/country/PL/towns/Krak%C3%B3w",
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				var response getTownSwiftCodesRes
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+				assert.Equal(t, "Kraków", response.TownName)
+				require.Len(t, response.SwiftCodes, 2)
+				assert.Equal(t, "CITIPLPXKRK", response.SwiftCodes[0].SwiftCode)
+				assert.Equal(t, "PKOPPLPWKRK", response.SwiftCodes[1].SwiftCode)
+			},
+		},
+		{
+			name:           "Unknown Town",
+			path:           "/v1/swift-codes/country/PL/towns/gdansk",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Invalid Country",
+			path:           "/v1/swift-codes/country/POL/towns",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Country Listing Still Served",
+			path:           "/v1/swift-codes/country/PL",
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Contains(t, w.Body.String(), "BPKOPLPWXXX")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			testServer.router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.checkResponse != nil {
+				tt.checkResponse(t, w)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/grysj/remitly-api/bic"
+	"github.com/grysj/remitly-api/db"
+)
+
+// maxPatchAttempts bounds the retries of a patch that raced with another
+// write.
+const maxPatchAttempts = 3
+
+// patchSwiftCode applies a JSON Merge Patch (RFC 7396) to the stored bank:
+// fields in the patch replace the stored ones, null clears a field and
+// omitted fields are kept.
+func (server *Server) patchSwiftCode(w http.ResponseWriter, r *http.Request) {
+	code, err := bic.Parse(r.PathValue("swiftcode"))
+	if err != nil {
+		http.Error(w, "Invalid Swift code format", http.StatusBadRequest)
+		return
+	}
+
+	var patch map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil || patch == nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// the patch is merged into the record it was read from and written only
+	// if that record is still stored, so concurrent patches never overwrite
+	// each other's fields. A lost race is retried while If-Match still holds.
+	precondition := parseIfMatch(r)
+	store := server.storeFor(r)
+	for attempt := 1; ; attempt++ {
+		current, err := store.GetBank(code.BIC11())
+		if err != nil {
+			log.Printf("Error retrieving bank details: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		if _, ok := precondition.condition(current); !ok {
+			http.Error(w, "Precondition failed", http.StatusPreconditionFailed)
+			return
+		}
+		if current == nil {
+			http.Error(w, "Bank not found", http.StatusNotFound)
+			return
+		}
+
+		patched, err := mergePatch(requestFromBank(*current), patch)
+		if err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		bank, ok := replacementBank(w, code, patched)
+		if !ok {
+			return
+		}
+
+		updated, err := store.UpdateBank(db.UpdateBankParams{
+			Bank:     bank,
+			Revision: current.Revision,
+		})
+		if errors.Is(err, db.ErrRevisionMismatch) {
+			if attempt < maxPatchAttempts {
+				continue
+			}
+			http.Error(w, "Bank was changed concurrently, retry the request", http.StatusConflict)
+			return
+		}
+		writeUpdatedBank(w, updated, err)
+		return
+	}
+}
+
+// requestFromBank is the document a merge patch is applied to. The country
+// name is left out because it always follows the ISO2 code.
+func requestFromBank(bank db.Bank) postSwiftCodeReq {
+	return postSwiftCodeReq{
+		Address:     bank.Address,
+		BankName:    bank.Name,
+		CountryISO2: bank.ISO2,
+		SwiftCode:   bank.Swift,
+		TownName:    bank.Town,
+		Timezone:    bank.Timezone,
+		CodeType:    bank.Type,
+	}
+}
+
+func mergePatch(target postSwiftCodeReq, patch map[string]interface{}) (postSwiftCodeReq, error) {
+	encoded, err := json.Marshal(target)
+	if err != nil {
+		return postSwiftCodeReq{}, err
+	}
+	var document map[string]interface{}
+	if err := json.Unmarshal(encoded, &document); err != nil {
+		return postSwiftCodeReq{}, err
+	}
+
+	for field, value := range patch {
+		if value == nil {
+			delete(document, field)
+			continue
+		}
+		document[field] = value
+	}
+
+	encoded, err = json.Marshal(document)
+	if err != nil {
+		return postSwiftCodeReq{}, err
+	}
+	var patched postSwiftCodeReq
+	if err := json.Unmarshal(encoded, &patched); err != nil {
+		return postSwiftCodeReq{}, err
+	}
+	return patched, nil
+}
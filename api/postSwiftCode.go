@@ -2,19 +2,73 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strings"
 
+	"github.com/grysj/remitly-api/bic"
+	"github.com/grysj/remitly-api/country"
 	"github.com/grysj/remitly-api/db"
+	"github.com/grysj/remitly-api/timezone"
 )
 
+const defaultCodeType = "BIC11"
+
 type postSwiftCodeReq struct {
 	Address     string `json:"address"`
 	BankName    string `json:"bankName"`
 	CountryISO2 string `json:"countryISO2"`
 	CountryName string `json:"countryName"`
 	SwiftCode   string `json:"swiftCode"`
+	TownName    string `json:"townName"`
+	Timezone    string `json:"timezone"`
+	CodeType    string `json:"codeType"`
+}
+
+// toBank validates the request and returns the bank to store. Errors are
+// meant for the client. It is shared by POST, PUT and PATCH.
+func (req postSwiftCodeReq) toBank() (db.Bank, error) {
+	if req.SwiftCode == "" {
+		return db.Bank{}, errors.New("Swift code is required")
+	}
+
+	code, err := bic.Parse(req.SwiftCode)
+	if err != nil {
+		return db.Bank{}, errors.New("Invalid Swift code format")
+	}
+
+	registered, ok := country.ByAlpha2(req.CountryISO2)
+	if !ok || len(req.CountryISO2) != 2 {
+		return db.Bank{}, errors.New("Invalid country ISO2 code")
+	}
+	if !code.MatchesCountry(registered.Alpha2) {
+		return db.Bank{}, errors.New("Country ISO2 code does not match the Swift code")
+	}
+	if req.CountryName != "" && !registered.Matches(req.CountryName) {
+		return db.Bank{}, errors.New("Country name does not match country ISO2 code")
+	}
+
+	zone, err := timezone.Normalize(req.Timezone)
+	if err != nil {
+		return db.Bank{}, errors.New("Invalid timezone: " + err.Error())
+	}
+
+	codeType := strings.ToUpper(strings.TrimSpace(req.CodeType))
+	if codeType == "" {
+		codeType = defaultCodeType
+	}
+
+	return db.Bank{
+		Swift:    code.BIC11(),
+		ISO2:     registered.Alpha2,
+		Name:     strings.ToUpper(req.BankName),
+		Type:     codeType,
+		Address:  req.Address,
+		Town:     strings.ToUpper(req.TownName),
+		Country:  registered.DisplayName(),
+		Timezone: zone,
+	}, nil
 }
 
 func (server *Server) postSwiftCode(w http.ResponseWriter, r *http.Request) {
@@ -24,25 +78,21 @@ func (server *Server) postSwiftCode(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if newBank.SwiftCode == "" {
-		http.Error(w, "Swift code is required", http.StatusBadRequest)
+	bankToAdd, err := newBank.toBank()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	if len(newBank.CountryISO2) != 2 {
-		http.Error(w, "Invalid country ISO2 code", http.StatusBadRequest)
+	created, err := server.storeFor(r).AddBankToDB(bankToAdd)
+	if errors.Is(err, db.ErrBankExists) {
+		if r.Header.Get("If-None-Match") == "*" {
+			http.Error(w, "Precondition failed", http.StatusPreconditionFailed)
+			return
+		}
+		http.Error(w, "Bank already exists", http.StatusConflict)
 		return
 	}
-
-	bankToAdd := db.Bank{
-		Swift:   newBank.SwiftCode,
-		ISO2:    strings.ToUpper(newBank.CountryISO2),
-		Name:    strings.ToUpper(newBank.BankName),
-		Address: newBank.Address,
-		Country: strings.ToUpper(newBank.CountryName),
-	}
-
-	err := server.store.AddBankToDB(bankToAdd)
 	if err != nil {
 		log.Printf("Error adding bank: %v", err)
 		http.Error(w, "Failed to add bank", http.StatusInternalServerError)
@@ -55,6 +105,7 @@ func (server *Server) postSwiftCode(w http.ResponseWriter, r *http.Request) {
 		Message: "Bank added successfully",
 	}
 
+	setETag(w, created.Revision)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	if err := json.NewEncoder(w).Encode(response); err != nil {
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/grysj/remitly-api/db"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -15,6 +16,7 @@ func TestPostSwiftCode(t *testing.T) {
 	require.NoError(t, testServer.store.CleanDB(testCtx))
 	tests := []struct {
 		name           string
+		setup          func(*testing.T)
 		requestBody    postSwiftCodeReq
 		expectedStatus int
 		checkResponse  func(*testing.T, *httptest.ResponseRecorder)
@@ -23,7 +25,7 @@ func TestPostSwiftCode(t *testing.T) {
 		{
 			name: "Successful Bank Addition",
 			requestBody: postSwiftCodeReq{
-				SwiftCode:   "EXAMPLEMCXXX",
+				SwiftCode:   "EXAMMCMCXXX",
 				BankName:    "Example Bank",
 				CountryISO2: "MC",
 				CountryName: "Monaco",
@@ -43,7 +45,7 @@ func TestPostSwiftCode(t *testing.T) {
 				require.NoError(t, err)
 				found := false
 				for _, bank := range banks {
-					if bank.Swift == "EXAMPLEMCXXX" {
+					if bank.Swift == "EXAMMCMCXXX" {
 						found = true
 						assert.Equal(t, "EXAMPLE BANK", bank.Name)
 						assert.Equal(t, "MC", bank.ISO2)
@@ -71,10 +73,46 @@ func TestPostSwiftCode(t *testing.T) {
 			},
 		},
 		{
-			name: "Invalid Country ISO2 Code",
+			name: "Invalid Swift Code",
 			requestBody: postSwiftCodeReq{
 				SwiftCode:   "EXAMPLEMCXXX",
 				BankName:    "Example Bank",
+				CountryISO2: "MC",
+				CountryName: "Monaco",
+			},
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Contains(t, w.Body.String(), "Invalid Swift code format")
+			},
+			checkRedis: func(t *testing.T) {
+				banks, err := testServer.store.GetBanksByISO2("MC")
+				require.NoError(t, err)
+				assert.Len(t, banks, 0)
+			},
+		},
+		{
+			name: "Eight-Character Code Is Stored As Headquarters",
+			requestBody: postSwiftCodeReq{
+				SwiftCode:   "EXAMMCMC",
+				BankName:    "Example Bank",
+				CountryISO2: "MC",
+				CountryName: "Monaco",
+			},
+			expectedStatus: http.StatusCreated,
+			checkResponse:  func(t *testing.T, w *httptest.ResponseRecorder) {},
+			checkRedis: func(t *testing.T) {
+				bank, err := testServer.store.GetBankFromSwift("EXAMMCMCXXX")
+				require.NoError(t, err)
+				require.NotNil(t, bank)
+				assert.Equal(t, "EXAMMCMCXXX", bank.Swift)
+				assert.True(t, bank.Headquater)
+			},
+		},
+		{
+			name: "Invalid Country ISO2 Code",
+			requestBody: postSwiftCodeReq{
+				SwiftCode:   "EXAMMCMCXXX",
+				BankName:    "Example Bank",
 				CountryISO2: "MONACO",
 				CountryName: "Monaco",
 			},
@@ -88,10 +126,148 @@ func TestPostSwiftCode(t *testing.T) {
 				assert.Len(t, banks, 0)
 			},
 		},
+		{
+			name: "Invalid Timezone",
+			requestBody: postSwiftCodeReq{
+				SwiftCode:   "EXAMMCMCXXX",
+				BankName:    "Example Bank",
+				CountryISO2: "MC",
+				CountryName: "Monaco",
+				Timezone:    "Europe/Monte_Carlo",
+			},
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Contains(t, w.Body.String(), "Invalid timezone")
+			},
+			checkRedis: func(t *testing.T) {
+				banks, err := testServer.store.GetBanksByISO2("MC")
+				require.NoError(t, err)
+				assert.Len(t, banks, 0)
+			},
+		},
+		{
+			name: "Deprecated Timezone Is Accepted",
+			requestBody: postSwiftCodeReq{
+				SwiftCode:   "EXAMUAUKXXX",
+				BankName:    "Example Bank",
+				CountryISO2: "UA",
+				CountryName: "Ukraine",
+				Timezone:    "Europe/Kiev",
+			},
+			expectedStatus: http.StatusCreated,
+			checkResponse:  func(t *testing.T, w *httptest.ResponseRecorder) {},
+			checkRedis: func(t *testing.T) {
+				banks, err := testServer.store.GetBanksByISO2("UA")
+				require.NoError(t, err)
+				assert.Len(t, banks, 1)
+			},
+		},
+		{
+			name: "Unknown Country ISO2 Code",
+			requestBody: postSwiftCodeReq{
+				SwiftCode:   "EXAMZZMCXXX",
+				BankName:    "Example Bank",
+				CountryISO2: "ZZ",
+				CountryName: "Nowhere",
+			},
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Contains(t, w.Body.String(), "Invalid country ISO2 code")
+			},
+			checkRedis: func(t *testing.T) {
+				banks, err := testServer.store.GetBanksByISO2("ZZ")
+				require.NoError(t, err)
+				assert.Len(t, banks, 0)
+			},
+		},
+		{
+			name: "Country Name Does Not Match Code",
+			requestBody: postSwiftCodeReq{
+				SwiftCode:   "EXAMMCMCXXX",
+				BankName:    "Example Bank",
+				CountryISO2: "MC",
+				CountryName: "Malta",
+			},
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Contains(t, w.Body.String(), "Country name does not match")
+			},
+			checkRedis: func(t *testing.T) {
+				name, err := testServer.store.GetCountryNameByISO2("MC")
+				require.NoError(t, err)
+				assert.Equal(t, "MONACO", name)
+				banks, err := testServer.store.GetBanksByISO2("MC")
+				require.NoError(t, err)
+				assert.Len(t, banks, 0)
+			},
+		},
+		{
+			name: "Country ISO2 Code Does Not Match Swift Code",
+			requestBody: postSwiftCodeReq{
+				SwiftCode:   "EXAMMCMCXXX",
+				BankName:    "Example Bank",
+				CountryISO2: "MT",
+			},
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Contains(t, w.Body.String(), "Country ISO2 code does not match the Swift code")
+			},
+			checkRedis: func(t *testing.T) {
+				banks, err := testServer.store.GetBanksByISO2("MT")
+				require.NoError(t, err)
+				assert.Len(t, banks, 0)
+			},
+		},
+		{
+			name: "Town And Code Type Are Stored",
+			requestBody: postSwiftCodeReq{
+				SwiftCode:   "EXAMMCMC001",
+				BankName:    "Example Bank",
+				CountryISO2: "MC",
+				TownName:    "Monte Carlo",
+				CodeType:    "bic11",
+			},
+			expectedStatus: http.StatusCreated,
+			checkResponse:  func(t *testing.T, w *httptest.ResponseRecorder) {},
+			checkRedis: func(t *testing.T) {
+				bank, err := testServer.store.GetBank("EXAMMCMC001")
+				require.NoError(t, err)
+				require.NotNil(t, bank)
+				assert.Equal(t, "MONTE CARLO", bank.Town)
+				assert.Equal(t, "BIC11", bank.Type)
+			},
+		},
+		{
+			name: "Existing Swift Code",
+			setup: func(t *testing.T) {
+				_, err := testServer.store.AddBankToDB(db.Bank{
+					Swift:   "EXAMMCMCXXX",
+					ISO2:    "MC",
+					Name:    "ORIGINAL BANK",
+					Country: "MONACO",
+				})
+				require.NoError(t, err)
+			},
+			requestBody: postSwiftCodeReq{
+				SwiftCode:   "EXAMMCMCXXX",
+				BankName:    "Example Bank",
+				CountryISO2: "MC",
+			},
+			expectedStatus: http.StatusConflict,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Contains(t, w.Body.String(), "Bank already exists")
+			},
+			checkRedis: func(t *testing.T) {
+				bank, err := testServer.store.GetBank("EXAMMCMCXXX")
+				require.NoError(t, err)
+				require.NotNil(t, bank)
+				assert.Equal(t, "ORIGINAL BANK", bank.Name, "an existing bank is not overwritten")
+			},
+		},
 		{
 			name: "Case Insensitive Input",
 			requestBody: postSwiftCodeReq{
-				SwiftCode:   "EXAMPLEMCXXX",
+				SwiftCode:   "EXAMMCMCXXX",
 				BankName:    "Example Bank",
 				CountryISO2: "mc",
 				CountryName: "Monaco",
@@ -111,7 +287,7 @@ func TestPostSwiftCode(t *testing.T) {
 				require.NoError(t, err)
 				found := false
 				for _, bank := range banks {
-					if bank.Swift == "EXAMPLEMCXXX" {
+					if bank.Swift == "EXAMMCMCXXX" {
 						found = true
 						assert.Equal(t, "EXAMPLE BANK", bank.Name)
 						assert.Equal(t, "MC", bank.ISO2)
@@ -125,6 +301,9 @@ func TestPostSwiftCode(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			require.NoError(t, testServer.store.CleanDB(testCtx))
+			if tt.setup != nil {
+				tt.setup(t)
+			}
 
 			body, err := json.Marshal(tt.requestBody)
 			require.NoError(t, err)
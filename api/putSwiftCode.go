@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/grysj/remitly-api/bic"
+	"github.com/grysj/remitly-api/db"
+)
+
+func (server *Server) putSwiftCode(w http.ResponseWriter, r *http.Request) {
+	code, err := bic.Parse(r.PathValue("swiftcode"))
+	if err != nil {
+		http.Error(w, "Invalid Swift code format", http.StatusBadRequest)
+		return
+	}
+
+	var replacement postSwiftCodeReq
+	if err := json.NewDecoder(r.Body).Decode(&replacement); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	condition, ok := server.ifMatchCondition(w, r, code)
+	if !ok {
+		return
+	}
+	server.replaceBank(w, r, code, replacement, condition)
+}
+
+// replaceBank stores req as the new version of the bank identified by code.
+// The write fails unless the stored record still meets condition.
+func (server *Server) replaceBank(w http.ResponseWriter, r *http.Request, code bic.BIC, req postSwiftCodeReq, condition writeCondition) {
+	bank, ok := replacementBank(w, code, req)
+	if !ok {
+		return
+	}
+	updated, err := server.storeFor(r).UpdateBank(db.UpdateBankParams{
+		Bank:      bank,
+		Revision:  condition.revision,
+		MustExist: condition.mustExist,
+	})
+	writeUpdatedBank(w, updated, err)
+}
+
+// replacementBank validates req as the new version of the bank identified
+// by code and writes a 400 response when it is invalid. The body may omit
+// the SWIFT code but must not change it.
+func replacementBank(w http.ResponseWriter, code bic.BIC, req postSwiftCodeReq) (db.Bank, bool) {
+	if req.SwiftCode == "" {
+		req.SwiftCode = code.BIC11()
+	}
+	if bodyCode, err := bic.Parse(req.SwiftCode); err == nil && bodyCode.BIC11() != code.BIC11() {
+		http.Error(w, "Swift code in body does not match the URL", http.StatusBadRequest)
+		return db.Bank{}, false
+	}
+
+	bank, err := req.toBank()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return db.Bank{}, false
+	}
+	return bank, true
+}
+
+func writeUpdatedBank(w http.ResponseWriter, updated *db.Bank, err error) {
+	if errors.Is(err, db.ErrBankNotFound) {
+		http.Error(w, "Bank not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, db.ErrRevisionMismatch) {
+		http.Error(w, "Precondition failed", http.StatusPreconditionFailed)
+		return
+	}
+	if err != nil {
+		log.Printf("Error updating bank: %v", err)
+		http.Error(w, "Failed to update bank", http.StatusInternalServerError)
+		return
+	}
+
+	setETag(w, updated.Revision)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]string{"message": "Bank updated successfully"}); err != nil {
+		log.Printf("Error encoding response: %v", err)
+		http.Error(w, "Error generating response", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,213 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/grysj/remitly-api/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateSwiftCode(t *testing.T) {
+	original := db.Bank{
+		Swift:    "AKBKMTMTXXX",
+		ISO2:     "MT",
+		Name:     "AKBANK T.A.S.",
+		Type:     "BIC11",
+		Address:  "PORTOMASO BUSINESS TOWER",
+		Town:     "ST. JULIAN'S",
+		Country:  "MALTA",
+		Timezone: "Europe/Malta",
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		swiftCode      string
+		body           string
+		expectedStatus int
+		checkResponse  func(*testing.T, *httptest.ResponseRecorder)
+		checkRedis     func(*testing.T)
+	}{
+		{
+			name:           "Put Replaces Every Field",
+			method:         http.MethodPut,
+			swiftCode:      "AKBKMTMTXXX",
+			body:           `{"bankName":"Akbank","countryISO2":"MT","townName":"Valletta"}`,
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Contains(t, w.Body.String(), "Bank updated successfully")
+			},
+			checkRedis: func(t *testing.T) {
+				bank, err := testServer.store.GetBank("AKBKMTMTXXX")
+				require.NoError(t, err)
+				require.NotNil(t, bank)
+				assert.Equal(t, "AKBANK", bank.Name)
+				assert.Equal(t, "VALLETTA", bank.Town)
+				assert.Empty(t, bank.Address)
+				assert.Empty(t, bank.Timezone)
+			},
+		},
+		{
+			name:           "Put Unknown Code",
+			method:         http.MethodPut,
+			swiftCode:      "ALBPPLP1BMW",
+			body:           `{"bankName":"Alior Bank","countryISO2":"PL"}`,
+			expectedStatus: http.StatusNotFound,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Contains(t, w.Body.String(), "Bank not found")
+			},
+			checkRedis: func(t *testing.T) {
+				bank, err := testServer.store.GetBank("ALBPPLP1BMW")
+				require.NoError(t, err)
+				assert.Nil(t, bank)
+			},
+		},
+		{
+			name:           "Put Different Code In Body",
+			method:         http.MethodPut,
+			swiftCode:      "AKBKMTMTXXX",
+			body:           `{"swiftCode":"ALBPPLP1BMW","bankName":"Akbank","countryISO2":"MT"}`,
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Contains(t, w.Body.String(), "does not match the URL")
+			},
+			checkRedis: func(t *testing.T) {},
+		},
+		{
+			name:           "Patch Keeps Omitted Fields",
+			method:         http.MethodPatch,
+			swiftCode:      "akbkmtmt",
+			body:           `{"bankName":"Akbank","timezone":null}`,
+			expectedStatus: http.StatusOK,
+			checkResponse:  func(t *testing.T, w *httptest.ResponseRecorder) {},
+			checkRedis: func(t *testing.T) {
+				bank, err := testServer.store.GetBank("AKBKMTMTXXX")
+				require.NoError(t, err)
+				require.NotNil(t, bank)
+				assert.Equal(t, "AKBANK", bank.Name)
+				assert.Equal(t, "PORTOMASO BUSINESS TOWER", bank.Address)
+				assert.Equal(t, "ST. JULIAN'S", bank.Town)
+				assert.Empty(t, bank.Timezone)
+			},
+		},
+		{
+			name:           "Patch Country Not Matching Code",
+			method:         http.MethodPatch,
+			swiftCode:      "AKBKMTMTXXX",
+			body:           `{"countryISO2":"PL"}`,
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Contains(t, w.Body.String(), "Country ISO2 code does not match the Swift code")
+			},
+			checkRedis: func(t *testing.T) {
+				malta, err := testServer.store.GetBanksByISO2("MT")
+				require.NoError(t, err)
+				require.Len(t, malta, 1)
+				assert.Equal(t, "AKBKMTMTXXX", malta[0].Swift)
+				poland, err := testServer.store.GetBanksByISO2("PL")
+				require.NoError(t, err)
+				assert.Empty(t, poland)
+			},
+		},
+		{
+			name:           "Patch Invalid Timezone",
+			method:         http.MethodPatch,
+			swiftCode:      "AKBKMTMTXXX",
+			body:           `{"timezone":"Mars/Olympus"}`,
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Contains(t, w.Body.String(), "Invalid timezone")
+			},
+			checkRedis: func(t *testing.T) {},
+		},
+		{
+			name:           "Patch With Wrong Field Type",
+			method:         http.MethodPatch,
+			swiftCode:      "AKBKMTMTXXX",
+			body:           `{"bankName":42}`,
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Contains(t, w.Body.String(), "Invalid request body")
+			},
+			checkRedis: func(t *testing.T) {},
+		},
+		{
+			name:           "Patch Unknown Code",
+			method:         http.MethodPatch,
+			swiftCode:      "ALBPPLP1BMW",
+			body:           `{"bankName":"Alior Bank"}`,
+			expectedStatus: http.StatusNotFound,
+			checkResponse:  func(t *testing.T, w *httptest.ResponseRecorder) {},
+			checkRedis:     func(t *testing.T) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, testServer.store.CleanDB(testCtx))
+			_, err := testServer.store.AddBankToDB(original)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(tt.method, "/v1/swift-codes/"+tt.swiftCode, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer "+password)
+			w := httptest.NewRecorder()
+
+			testServer.router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			tt.checkResponse(t, w)
+			tt.checkRedis(t)
+		})
+	}
+	testServer.store.CleanDB(testCtx)
+}
+
+func TestConcurrentPatches(t *testing.T) {
+	require.NoError(t, testServer.store.CleanDB(testCtx))
+	defer testServer.store.CleanDB(testCtx)
+
+	for round := 0; round < 5; round++ {
+		require.NoError(t, testServer.store.CleanDB(testCtx))
+		_, err := testServer.store.AddBankToDB(db.Bank{Swift: "AKBKMTMTXXX", ISO2: "MT", Name: "AKBANK T.A.S.", Country: "MALTA"})
+		require.NoError(t, err)
+
+		bodies := []string{`{"bankName":"Akbank"}`, `{"townName":"Valletta"}`, `{"address":"Portomaso"}`}
+		codes := make([]int, len(bodies))
+		var wg sync.WaitGroup
+		for i, body := range bodies {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				req := httptest.NewRequest(http.MethodPatch, "/v1/swift-codes/AKBKMTMTXXX", strings.NewReader(body))
+				req.Header.Set("Authorization", "Bearer "+password)
+				w := httptest.NewRecorder()
+				testServer.router.ServeHTTP(w, req)
+				codes[i] = w.Code
+			}()
+		}
+		wg.Wait()
+
+		bank, err := testServer.store.GetBank("AKBKMTMTXXX")
+		require.NoError(t, err)
+		require.NotNil(t, bank)
+		for i, code := range codes {
+			if code != http.StatusOK {
+				assert.Equal(t, http.StatusConflict, code, bodies[i])
+				continue
+			}
+			switch i {
+			case 0:
+				assert.Equal(t, "AKBANK", bank.Name)
+			case 1:
+				assert.Equal(t, "VALLETTA", bank.Town)
+			case 2:
+				assert.Equal(t, "Portomaso", bank.Address)
+			}
+		}
+	}
+}
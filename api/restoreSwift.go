@@ -0,0 +1,70 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/grysj/remitly-api/bic"
+	"github.com/grysj/remitly-api/db"
+)
+
+type restoreSwiftRes struct {
+	Message  string   `json:"message"`
+	Restored []string `json:"restored"`
+}
+
+func (server *Server) restoreSwift(w http.ResponseWriter, r *http.Request) {
+	code, err := bic.Parse(r.PathValue("swiftcode"))
+	if err != nil {
+		http.Error(w, "Invalid Swift code format", http.StatusBadRequest)
+		return
+	}
+
+	banks, err := server.storeFor(r).RestoreBank(code.BIC11())
+	if errors.Is(err, db.ErrBankNotDeleted) {
+		http.Error(w, "Deleted bank not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, db.ErrBankExists) {
+		http.Error(w, "Bank already exists", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		log.Printf("Error restoring %s: %v", code, err)
+		http.Error(w, "Failed to restore bank", http.StatusInternalServerError)
+		return
+	}
+
+	response := restoreSwiftRes{
+		Message:  "Successfully restored",
+		Restored: make([]string, 0, len(banks)),
+	}
+	for _, bank := range banks {
+		response.Restored = append(response.Restored, bank.Swift)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding response: %v", err)
+		http.Error(w, "Error generating response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (server *Server) getDeletedSwiftCodes(w http.ResponseWriter, r *http.Request) {
+	deleted, err := server.storeFor(r).ListDeletedBanks()
+	if err != nil {
+		log.Printf("Error listing deleted banks: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string][]db.DeletedBank{"swiftCodes": deleted}); err != nil {
+		log.Printf("Error encoding response: %v", err)
+		http.Error(w, "Error generating response", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grysj/remitly-api/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestoreSwift(t *testing.T) {
+	require.NoError(t, testServer.store.CleanDB(testCtx))
+	defer testServer.store.CleanDB(testCtx)
+
+	for _, bank := range []db.Bank{
+		{Swift: "BCHICLRMXXX", ISO2: "CL", Name: "BANCO CENTRAL DE CHILE", Country: "CHILE"},
+		{Swift: "BCHICLRM001", ISO2: "CL", Name: "BANCO DE CHILE BRANCH 1", Country: "CHILE"},
+	} {
+		_, err := testServer.store.AddBankToDB(bank)
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+		checkResponse  func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name:           "Delete Headquarters",
+			method:         http.MethodDelete,
+			path:           "/v1/swift-codes/BCHICLRMXXX",
+			expectedStatus: http.StatusOK,
+			checkResponse:  func(t *testing.T, w *httptest.ResponseRecorder) {},
+		},
+		{
+			name:           "Deleted Code Is Hidden",
+			method:         http.MethodGet,
+			path:           "/v1/swift-codes/BCHICLRM001",
+			expectedStatus: http.StatusNotFound,
+			checkResponse:  func(t *testing.T, w *httptest.ResponseRecorder) {},
+		},
+		{
+			name:           "List Deleted Codes",
+			method:         http.MethodGet,
+			path:           "/v1/admin/deleted",
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				var response map[string][]db.DeletedBank
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+				require.Len(t, response["swiftCodes"], 2)
+				assert.Equal(t, db.DefaultActor, response["swiftCodes"][0].DeletedBy)
+			},
+		},
+		{
+			name:           "Restore Headquarters With Branches",
+			method:         http.MethodPost,
+			path:           "/v1/swift-codes/bchiclrm/restore",
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				var response restoreSwiftRes
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+				assert.Equal(t, []string{"BCHICLRM001", "BCHICLRMXXX"}, response.Restored)
+			},
+		},
+		{
+			name:           "Restored Code Is Visible",
+			method:         http.MethodGet,
+			path:           "/v1/swift-codes/BCHICLRM001",
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Contains(t, w.Body.String(), "BANCO DE CHILE BRANCH 1")
+			},
+		},
+		{
+			name:           "Restore Twice",
+			method:         http.MethodPost,
+			path:           "/v1/swift-codes/BCHICLRMXXX/restore",
+			expectedStatus: http.StatusNotFound,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Contains(t, w.Body.String(), "Deleted bank not found")
+			},
+		},
+		{
+			name:           "Invalid Code",
+			method:         http.MethodPost,
+			path:           "/v1/swift-codes/TESTXX/restore",
+			expectedStatus: http.StatusBadRequest,
+			checkResponse:  func(t *testing.T, w *httptest.ResponseRecorder) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+password)
+			w := httptest.NewRecorder()
+
+			testServer.router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			tt.checkResponse(t, w)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/grysj/remitly-api/db"
+)
+
+type searchHitRes struct {
+	BankInfo
+	TownName string  `json:"townName,omitempty"`
+	Score    float64 `json:"score"`
+}
+
+type searchSwiftCodesRes struct {
+	Query   string         `json:"query"`
+	Total   int            `json:"total"`
+	Limit   int            `json:"limit"`
+	Offset  int            `json:"offset"`
+	Results []searchHitRes `json:"results"`
+}
+
+// searchSwiftCodes finds codes whose bank name, town or address contain
+// every word of q. Results can be narrowed to one country and are paged
+// with limit and offset.
+func (server *Server) searchSwiftCodes(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	q := strings.TrimSpace(query.Get("q"))
+	if q == "" {
+		http.Error(w, "Missing search query", http.StatusBadRequest)
+		return
+	}
+
+	country := strings.ToUpper(query.Get("country"))
+	if country != "" && len(country) != 2 {
+		http.Error(w, "Invalid country code format", http.StatusBadRequest)
+		return
+	}
+
+	limit, err := queryInt(query.Get("limit"), db.DefaultSearchLimit)
+	if err != nil || limit < 1 || limit > db.MaxSearchLimit {
+		http.Error(w, "Invalid limit", http.StatusBadRequest)
+		return
+	}
+	offset, err := queryInt(query.Get("offset"), 0)
+	if err != nil || offset < 0 {
+		http.Error(w, "Invalid offset", http.StatusBadRequest)
+		return
+	}
+
+	result, err := server.storeFor(r).SearchBanks(db.SearchBanksParams{
+		Query:  q,
+		ISO2:   country,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if errors.Is(err, db.ErrEmptyQuery) {
+		http.Error(w, "Search query has no words", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("Error searching banks: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	response := searchSwiftCodesRes{
+		Query:   q,
+		Total:   result.Total,
+		Limit:   limit,
+		Offset:  offset,
+		Results: make([]searchHitRes, 0, len(result.Hits)),
+	}
+	for _, hit := range result.Hits {
+		response.Results = append(response.Results, searchHitRes{
+			BankInfo: BankInfo{
+				Address:      hit.Address,
+				BankName:     hit.Name,
+				CountryISO2:  hit.ISO2,
+				IsHeadquater: hit.Headquater,
+				SwiftCode:    hit.Swift,
+			},
+			TownName: hit.Town,
+			Score:    hit.Score,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding response: %v", err)
+		http.Error(w, "Error generating response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func queryInt(value string, defaultValue int) (int, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	return strconv.Atoi(value)
+}
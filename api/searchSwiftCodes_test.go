@@ -0,0 +1,97 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grysj/remitly-api/db"
+	"github.com/grysj/remitly-api/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchSwiftCodes(t *testing.T) {
+	require.NoError(t, testServer.store.CleanDB(testCtx))
+	defer testServer.store.CleanDB(testCtx)
+
+	_, err := testServer.store.ReloadDataset(db.ReloadDatasetParams{Rows: []parser.CsvRow{
+		{ISO2: "PL", Swift: "BPKOPLPWXXX", Name: "PKO BANK POLSKI S.A.", Address: "PULAWSKA 15", Town: "WARSZAWA", Country: "POLAND"},
+		{ISO2: "PL", Swift: "ALBPPLP1BMW", Name: "ALIOR BANK S.A.", Address: "LOPUSZANSKA 38D", Town: "WARSZAWA", Country: "POLAND"},
+		{ISO2: "MT", Swift: "AKBKMTMTXXX", Name: "AKBANK T.A.S.", Address: "PORTOMASO BUSINESS TOWER", Town: "ST. JULIAN'S", Country: "MALTA"},
+	}})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		wantTotal      int
+		want           []string
+	}{
+		{
+			name:           "Search",
+			query:          "q=bank+warszawa",
+			expectedStatus: http.StatusOK,
+			wantTotal:      2,
+			want:           []string{"ALBPPLP1BMW", "BPKOPLPWXXX"},
+		},
+		{
+			name:           "Country And Paging",
+			query:          "q=warszawa&country=pl&limit=1&offset=1",
+			expectedStatus: http.StatusOK,
+			wantTotal:      2,
+			want:           []string{"BPKOPLPWXXX"},
+		},
+		{
+			name:           "Town With Apostrophe",
+			query:          "q=julian%27s",
+			expectedStatus: http.StatusOK,
+			wantTotal:      1,
+			want:           []string{"AKBKMTMTXXX"},
+		},
+		{
+			name:           "Missing Query",
+			query:          "",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Query Without Words",
+			query:          "q=S.A.",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid Limit",
+			query:          "q=bank&limit=1000",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid Country",
+			query:          "q=bank&country=POL",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/swift-codes/search?"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			testServer.router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var response searchSwiftCodesRes
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+			assert.Equal(t, tt.wantTotal, response.Total)
+			codes := make([]string, 0, len(response.Results))
+			for _, result := range response.Results {
+				codes = append(codes, result.SwiftCode)
+			}
+			assert.Equal(t, tt.want, codes)
+		})
+	}
+}
@@ -7,35 +7,66 @@ import (
 
 	"github.com/grysj/remitly-api/config"
 	"github.com/grysj/remitly-api/db"
+	"github.com/grysj/remitly-api/iban"
+	"github.com/grysj/remitly-api/parser"
 	"github.com/rs/cors"
 )
 
 type Server struct {
-	store  *db.Store
-	router http.Handler
+	store        *db.Store
+	router       http.Handler
+	importParams parser.ParseParams
+	bankCodes    iban.BankCodes
 }
 
 func NewServer(store *db.Store, cfg config.Config) (*Server, error) {
 
 	mux := http.NewServeMux()
 
+	importParams, err := cfg.ImportParams()
+	if err != nil {
+		return nil, fmt.Errorf("invalid import configuration: %w", err)
+	}
+
+	bankCodes, err := iban.LoadBankCodes(cfg.IbanBankCodesPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid IBAN bank codes: %w", err)
+	}
+
 	server := &Server{
-		store: store,
+		store:        store,
+		importParams: importParams,
+		bankCodes:    bankCodes,
 	}
 
 	mux.HandleFunc("GET /v1/swift-codes/{swiftcode...}", server.getSwiftDetails)
 	mux.HandleFunc("GET /v1/swift-codes/country/{countryISO2code...}", server.getSwiftCodes)
+	mux.HandleFunc("GET /v1/swift-codes/country/{countryISO2code}/towns", server.getTowns)
+	mux.HandleFunc("GET /v1/swift-codes/country/{countryISO2code}/towns/{town}", server.getTownSwiftCodes)
+	mux.HandleFunc("GET /v1/swift-codes/search", server.searchSwiftCodes)
+	mux.HandleFunc("GET /v1/swift-codes/autocomplete", server.autocomplete)
+	mux.HandleFunc("GET /v1/iban/{iban}", server.getIban)
 	mux.HandleFunc("POST /v1/swift-codes", Middleware(cfg.ApiPassword, server.postSwiftCode))
+	mux.HandleFunc("PUT /v1/swift-codes/{swiftcode}", Middleware(cfg.ApiPassword, server.putSwiftCode))
+	mux.HandleFunc("PATCH /v1/swift-codes/{swiftcode}", Middleware(cfg.ApiPassword, server.patchSwiftCode))
 	mux.HandleFunc("DELETE /v1/swift-codes/{swiftcode...}", Middleware(cfg.ApiPassword, server.deleteSwift))
+	mux.HandleFunc("POST /v1/swift-codes/{swiftcode}/restore", Middleware(cfg.ApiPassword, server.restoreSwift))
+	mux.HandleFunc("POST /v1/admin/validate", Middleware(cfg.ApiPassword, server.validateDataset))
+	mux.HandleFunc("GET /v1/admin/deleted", Middleware(cfg.ApiPassword, server.getDeletedSwiftCodes))
+	mux.HandleFunc("GET /v1/admin/datasets", Middleware(cfg.ApiPassword, server.listDatasets))
+	mux.HandleFunc("POST /v1/admin/datasets/{id}/activate", Middleware(cfg.ApiPassword, server.activateDataset))
+	mux.HandleFunc("GET /v1/admin/fsck", Middleware(cfg.ApiPassword, server.checkConsistency))
+	mux.HandleFunc("POST /v1/admin/fsck", Middleware(cfg.ApiPassword, server.checkConsistency))
 	mux.HandleFunc("/", server.notFoundHandler)
 
 	c := cors.New(cors.Options{
 		AllowedOrigins: cfg.CorsAllowedOrigins,
 		AllowedMethods: cfg.CorsAllowedMethods,
 		AllowedHeaders: cfg.CorsAllowedHeaders,
+		ExposedHeaders: []string{datasetVersionHeader, "ETag"},
 	})
 
-	server.router = c.Handler(mux)
+	server.router = c.Handler(server.withRequestStore(mux))
 
 	return server, nil
 
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/grysj/remitly-api/parser"
+)
+
+const maxDatasetUploadBytes = 64 << 20
+
+type validateDatasetRes struct {
+	RowErrors []parser.RowError `json:"rowErrors"`
+	parser.ValidationReport
+}
+
+func (server *Server) validateDataset(w http.ResponseWriter, r *http.Request) {
+	body := http.MaxBytesReader(w, r.Body, maxDatasetUploadBytes)
+
+	params := server.importParams
+	params.Mode = parser.Lenient
+
+	parsed, err := parser.ParseReader(body, "", params)
+	if err != nil {
+		http.Error(w, "Invalid dataset: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report := parser.Validate(parsed.Rows)
+	response := validateDatasetRes{
+		RowErrors:        make([]parser.RowError, 0, len(parsed.Errors)),
+		ValidationReport: report,
+	}
+	response.RowErrors = append(response.RowErrors, parsed.Errors...)
+	response.Valid = report.Valid && len(parsed.Errors) == 0
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding response: %v", err)
+		http.Error(w, "Error generating response", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/grysj/remitly-api/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateDataset(t *testing.T) {
+	header := "COUNTRY ISO2 CODE,SWIFT CODE,CODE TYPE,NAME,ADDRESS,TOWN NAME,COUNTRY NAME,TIME ZONE\n"
+
+	tests := []struct {
+		name           string
+		body           string
+		authorized     bool
+		expectedStatus int
+		checkResponse  func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Consistent Dataset",
+			body: header +
+				"AL,AAISALTRXXX,BIC11,UNITED BANK OF ALBANIA SH.A,,TIRANA,ALBANIA,Europe/Tirane\n" +
+				"AL,AAISALTR001,BIC11,UNITED BANK OF ALBANIA SH.A,,DURRES,ALBANIA,Europe/Tirane\n",
+			authorized:     true,
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				var response validateDatasetRes
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+				assert.True(t, response.Valid)
+				assert.Equal(t, 2, response.Rows)
+				assert.Empty(t, response.Issues)
+			},
+		},
+		{
+			name: "Inconsistent Dataset",
+			body: header +
+				"AL,AAISALTRXXX,BIC11,UNITED BANK OF ALBANIA SH.A,,TIRANA,ALBANIA,Europe/Tirane\n" +
+				"AL,AAISALTRXXX,BIC11,UNITED BANK OF ALBANIA SH.A,,TIRANA,ALBANIA,Europe/Tirane\n" +
+				"AL,ABCDPLPW,BIC8,SOME BANK,,TIRANA,REPUBLIC OF ALBANIA,Europe/Tirane\n",
+			authorized:     true,
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				var response validateDatasetRes
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+				assert.False(t, response.Valid)
+
+				kinds := make(map[string]bool)
+				for _, issue := range response.Issues {
+					kinds[issue.Kind] = true
+				}
+				assert.True(t, kinds[parser.IssueDuplicateSwift])
+				assert.True(t, kinds[parser.IssueCountryMismatch])
+				assert.True(t, kinds[parser.IssueConflictingCountries])
+			},
+		},
+		{
+			name:           "Missing Columns",
+			body:           "SWIFT CODE,NAME\nAAISALTRXXX,UNITED BANK OF ALBANIA SH.A\n",
+			authorized:     true,
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Contains(t, w.Body.String(), "Invalid dataset")
+			},
+		},
+		{
+			name:           "Unauthorized",
+			body:           header,
+			authorized:     false,
+			expectedStatus: http.StatusUnauthorized,
+			checkResponse:  func(t *testing.T, w *httptest.ResponseRecorder) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/v1/admin/validate", strings.NewReader(tt.body))
+			if tt.authorized {
+				req.Header.Set("Authorization", "Bearer "+password)
+			}
+			w := httptest.NewRecorder()
+
+			testServer.router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			tt.checkResponse(t, w)
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package bic
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	headquartersBranch = "XXX"
+	bic8Length         = 8
+	bic11Length        = 11
+)
+
+var (
+	ErrInvalidLength     = errors.New("BIC must have 8 or 11 characters")
+	ErrInvalidCharacters = errors.New("BIC contains invalid characters")
+)
+
+// BIC is a business identifier code (ISO 9362) split into its parts.
+// Branch is empty for codes given in the 8-character form.
+type BIC struct {
+	Institution string
+	Country     string
+	Location    string
+	Branch      string
+}
+
+// Parse validates an 8 or 11 character code. Surrounding whitespace is
+// ignored and letters are upper-cased.
+func Parse(code string) (BIC, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != bic8Length && len(code) != bic11Length {
+		return BIC{}, fmt.Errorf("%q: %w", code, ErrInvalidLength)
+	}
+	for i := 0; i < len(code); i++ {
+		c := code[i]
+		isLetter := c >= 'A' && c <= 'Z'
+		isDigit := c >= '0' && c <= '9'
+		// the country code is the only part that must be letters only
+		if !isLetter && (!isDigit || (i >= 4 && i < 6)) {
+			return BIC{}, fmt.Errorf("%q: %w", code, ErrInvalidCharacters)
+		}
+	}
+
+	return BIC{
+		Institution: code[:4],
+		Country:     code[4:6],
+		Location:    code[6:8],
+		Branch:      code[8:],
+	}, nil
+}
+
+func IsValid(code string) bool {
+	_, err := Parse(code)
+	return err == nil
+}
+
+// String returns the code in the form it was parsed from.
+func (b BIC) String() string {
+	return b.Institution + b.Country + b.Location + b.Branch
+}
+
+func (b BIC) BIC8() string {
+	return b.Institution + b.Country + b.Location
+}
+
+func (b BIC) BIC11() string {
+	if b.Branch == "" {
+		return b.BIC8() + headquartersBranch
+	}
+	return b.String()
+}
+
+func (b BIC) IsBIC8() bool {
+	return b.Branch == ""
+}
+
+// Format names the form the code was given in, "BIC8" or "BIC11".
+func (b BIC) Format() string {
+	if b.IsBIC8() {
+		return "BIC8"
+	}
+	return "BIC11"
+}
+
+func (b BIC) IsHeadquarters() bool {
+	return b.Branch == "" || b.Branch == headquartersBranch
+}
+
+// Headquarters returns the primary office of the institution in its
+// 11-character form.
+func (b BIC) Headquarters() BIC {
+	b.Branch = headquartersBranch
+	return b
+}
+
+// MatchesCountry reports whether the country letters of the code are the
+// given ISO 3166 alpha-2 code.
+func (b BIC) MatchesCountry(iso2 string) bool {
+	return b.Country == strings.ToUpper(strings.TrimSpace(iso2))
+}
+
+// IsTestBIC reports codes reserved for test and training, which have 0 as
+// the second location character.
+func (b BIC) IsTestBIC() bool {
+	return len(b.Location) == 2 && b.Location[1] == '0'
+}
+
+// IsPassiveParticipant reports codes of institutions that are not
+// connected to the SWIFT network, which have 1 as the second location
+// character.
+func (b BIC) IsPassiveParticipant() bool {
+	return len(b.Location) == 2 && b.Location[1] == '1'
+}
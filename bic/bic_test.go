@@ -0,0 +1,80 @@
+package bic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    string
+		want    BIC
+		wantErr error
+	}{
+		{
+			name: "headquarters",
+			code: "AAISALTRXXX",
+			want: BIC{Institution: "AAIS", Country: "AL", Location: "TR", Branch: "XXX"},
+		},
+		{
+			name: "branch",
+			code: "ALBPPLP1BMW",
+			want: BIC{Institution: "ALBP", Country: "PL", Location: "P1", Branch: "BMW"},
+		},
+		{
+			name: "eight characters, lower case",
+			code: " bchiclrm ",
+			want: BIC{Institution: "BCHI", Country: "CL", Location: "RM"},
+		},
+		{name: "too short", code: "XX", wantErr: ErrInvalidLength},
+		{name: "empty", code: "", wantErr: ErrInvalidLength},
+		{name: "twelve characters", code: "EXAMPLEMCXXX", wantErr: ErrInvalidLength},
+		{name: "digit in country", code: "AAIS1LTRXXX", wantErr: ErrInvalidCharacters},
+		{name: "punctuation", code: "AAIS-ALTXXX", wantErr: ErrInvalidCharacters},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.code)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.False(t, IsValid(tt.code))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBICHelpers(t *testing.T) {
+	hq, err := Parse("BCHICLRM")
+	require.NoError(t, err)
+	assert.True(t, hq.IsBIC8())
+	assert.True(t, hq.IsHeadquarters())
+	assert.Equal(t, "BCHICLRM", hq.String())
+	assert.Equal(t, "BCHICLRMXXX", hq.BIC11())
+	assert.Equal(t, "BIC8", hq.Format())
+
+	branch, err := Parse("BCHICLRM001")
+	require.NoError(t, err)
+	assert.False(t, branch.IsHeadquarters())
+	assert.Equal(t, "BIC11", branch.Format())
+	assert.Equal(t, "BCHICLRM", branch.BIC8())
+	assert.Equal(t, "BCHICLRMXXX", branch.Headquarters().String())
+	assert.True(t, branch.MatchesCountry("cl"))
+	assert.False(t, branch.MatchesCountry("PL"))
+
+	test, err := Parse("DEUTDEF0XXX")
+	require.NoError(t, err)
+	assert.True(t, test.IsTestBIC())
+	assert.False(t, test.IsPassiveParticipant())
+
+	passive, err := Parse("ALBPPLP1BMW")
+	require.NoError(t, err)
+	assert.True(t, passive.IsPassiveParticipant())
+	assert.False(t, passive.IsTestBIC())
+}
@@ -1,8 +1,13 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/grysj/remitly-api/parser"
 )
 
 type Config struct {
@@ -14,7 +19,17 @@ type Config struct {
 	RedisPort     string
 	RedisPassword string
 
-	CsvPath string
+	CsvPath           string
+	ImportMode        string
+	ColumnMappingPath string
+	ColumnMapping     string
+	ImportEncoding    string
+	CsvDelimiter      string
+
+	IbanBankCodesPath string
+
+	DatasetRetention int
+	DeletedRetention time.Duration
 
 	ApiPassword string
 }
@@ -22,16 +37,68 @@ type Config struct {
 func LoadConfig() *Config {
 	return &Config{
 		CorsAllowedOrigins: strings.Split(getEnvOrDefault("CORS_ALLOWED_ORIGINS", "*"), ","),
-		CorsAllowedMethods: strings.Split(getEnvOrDefault("CORS_ALLOWED_METHODS", "GET,POST,DELETE"), ","),
-		CorsAllowedHeaders: strings.Split(getEnvOrDefault("CORS_ALLOWED_HEADERS", "Accept,Authorization,Content-Type"), ","),
+		CorsAllowedMethods: strings.Split(getEnvOrDefault("CORS_ALLOWED_METHODS", "GET,POST,PUT,PATCH,DELETE"), ","),
+		CorsAllowedHeaders: strings.Split(getEnvOrDefault("CORS_ALLOWED_HEADERS", "Accept,Authorization,Content-Type,If-Match,If-None-Match,X-Actor"), ","),
 
 		RedisHost:     getEnvOrDefault("REDIS_HOST", "redis"),
 		RedisPort:     getEnvOrDefault("REDIS_PORT", "6379"),
 		RedisPassword: getEnvOrDefault("REDIS_PASSWORD", ""),
 
-		CsvPath:     getEnvOrDefault("CV_PATH", "SWIFT_CODES.csv"),
-		ApiPassword: getEnvOrDefault("API_PASSWORD", "secret123"),
+		CsvPath:           getEnvOrDefault("CV_PATH", "SWIFT_CODES.csv"),
+		ImportMode:        getEnvOrDefault("IMPORT_MODE", "strict"),
+		ColumnMappingPath: getEnvOrDefault("COLUMN_MAPPING_FILE", ""),
+		ColumnMapping:     getEnvOrDefault("COLUMN_MAPPING", ""),
+		ImportEncoding:    getEnvOrDefault("IMPORT_ENCODING", "auto"),
+		CsvDelimiter:      getEnvOrDefault("CSV_DELIMITER", ""),
+		IbanBankCodesPath: getEnvOrDefault("IBAN_BANK_CODES_FILE", ""),
+		DatasetRetention:  getEnvIntOrDefault("DATASET_RETENTION", 5),
+		DeletedRetention:  getEnvDurationOrDefault("DELETED_RETENTION", 30*24*time.Hour),
+		ApiPassword:       getEnvOrDefault("API_PASSWORD", "secret123"),
+	}
+}
+
+func (c Config) ImportParams() (parser.ParseParams, error) {
+	mode, err := parser.ParseModeFromString(c.ImportMode)
+	if err != nil {
+		return parser.ParseParams{}, err
+	}
+
+	columns, err := parser.LoadColumnMapping(c.ColumnMappingPath, c.ColumnMapping)
+	if err != nil {
+		return parser.ParseParams{}, err
+	}
+
+	encoding, err := parser.NormalizeEncoding(c.ImportEncoding)
+	if err != nil {
+		return parser.ParseParams{}, err
+	}
+
+	delimiter, err := parseDelimiter(c.CsvDelimiter)
+	if err != nil {
+		return parser.ParseParams{}, err
 	}
+
+	return parser.ParseParams{
+		Mode:      mode,
+		Columns:   columns,
+		Encoding:  encoding,
+		Delimiter: delimiter,
+	}, nil
+}
+
+func parseDelimiter(value string) (rune, error) {
+	switch value {
+	case "":
+		return 0, nil
+	case "tab", `\t`:
+		return '\t', nil
+	}
+
+	runes := []rune(value)
+	if len(runes) != 1 {
+		return 0, fmt.Errorf("invalid CSV_DELIMITER %q: expected a single character or \"tab\"", value)
+	}
+	return runes[0], nil
 }
 
 func getEnvOrDefault(key, defaultValue string) string {
@@ -40,3 +107,19 @@ func getEnvOrDefault(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvIntOrDefault(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvDurationOrDefault(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,135 @@
+package country
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+//go:embed iso3166.tab
+var registryTab []byte
+
+type Country struct {
+	Alpha2       string `json:"alpha2"`
+	Alpha3       string `json:"alpha3"`
+	Numeric      string `json:"numeric"`
+	Name         string `json:"name"`
+	OfficialName string `json:"officialName,omitempty"`
+	CommonName   string `json:"commonName,omitempty"`
+}
+
+var (
+	byAlpha2  = make(map[string]Country)
+	byAlpha3  = make(map[string]Country)
+	byNumeric = make(map[string]Country)
+)
+
+func init() {
+	scanner := bufio.NewScanner(bytes.NewReader(registryTab))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) < 6 {
+			continue
+		}
+		c := Country{
+			Alpha2:       fields[0],
+			Alpha3:       fields[1],
+			Numeric:      fields[2],
+			Name:         fields[3],
+			OfficialName: fields[4],
+			CommonName:   fields[5],
+		}
+		byAlpha2[c.Alpha2] = c
+		byAlpha3[c.Alpha3] = c
+		if c.Numeric != "" {
+			byNumeric[c.Numeric] = c
+		}
+	}
+}
+
+func ByAlpha2(code string) (Country, bool) {
+	c, ok := byAlpha2[strings.ToUpper(strings.TrimSpace(code))]
+	return c, ok
+}
+
+func ByAlpha3(code string) (Country, bool) {
+	c, ok := byAlpha3[strings.ToUpper(strings.TrimSpace(code))]
+	return c, ok
+}
+
+func ByNumeric(code string) (Country, bool) {
+	c, ok := byNumeric[strings.TrimSpace(code)]
+	return c, ok
+}
+
+// DisplayName is the short name in the upper-case form the store uses,
+// e.g. "POLAND".
+func (c Country) DisplayName() string {
+	return strings.ToUpper(c.Name)
+}
+
+// Matches reports whether name refers to the country by its short,
+// official or common name. Case, accents and punctuation are ignored, and
+// inverted names such as "Korea, Republic of" also match in natural order.
+func (c Country) Matches(name string) bool {
+	want := normalizeName(name)
+	if want == "" {
+		return false
+	}
+	for _, candidate := range []string{c.Name, c.OfficialName, c.CommonName} {
+		if candidate == "" {
+			continue
+		}
+		if normalizeName(candidate) == want {
+			return true
+		}
+		if head, tail, ok := strings.Cut(candidate, ", "); ok && normalizeName(tail+" "+head) == want {
+			return true
+		}
+	}
+	return false
+}
+
+// letterFolder maps the Latin letters that are distinct letters rather
+// than a base letter with a diacritic, so decomposition leaves them as they
+// are.
+var letterFolder = strings.NewReplacer(
+	"Đ", "D", "ı", "I", "Ł", "L", "Ø", "O", "Æ", "AE", "ß", "SS", "ẞ", "SS",
+)
+
+// FoldAccents upper-cases s and replaces accented Latin letters with their
+// base letter, so "Kraków" and "KRAKOW" compare equal. Letters are
+// decomposed and their combining marks dropped, which covers every
+// precomposed letter such as "Ș", "Ī" or "İ".
+func FoldAccents(s string) string {
+	decomposed := norm.NFD.String(strings.ToUpper(s))
+	stripped := strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+		return r
+	}, decomposed)
+	return letterFolder.Replace(norm.NFC.String(stripped))
+}
+
+func normalizeName(name string) string {
+	folded := FoldAccents(name)
+	folded = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		if r == '\'' || r == '’' {
+			return -1
+		}
+		return ' '
+	}, folded)
+	return strings.Join(strings.Fields(folded), " ")
+}
@@ -0,0 +1,86 @@
+package country
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup(t *testing.T) {
+	pl, ok := ByAlpha2("pl")
+	require.True(t, ok)
+	assert.Equal(t, Country{Alpha2: "PL", Alpha3: "POL", Numeric: "616", Name: "Poland", OfficialName: "Republic of Poland"}, pl)
+	assert.Equal(t, "POLAND", pl.DisplayName())
+
+	byAlpha3, ok := ByAlpha3("URY")
+	require.True(t, ok)
+	assert.Equal(t, "UY", byAlpha3.Alpha2)
+
+	byNumeric, ok := ByNumeric("533")
+	require.True(t, ok)
+	assert.Equal(t, "AW", byNumeric.Alpha2)
+
+	kosovo, ok := ByAlpha2("XK")
+	require.True(t, ok)
+	assert.Equal(t, "KOSOVO", kosovo.DisplayName())
+
+	_, ok = ByAlpha2("XX")
+	assert.False(t, ok)
+	_, ok = ByAlpha2("")
+	assert.False(t, ok)
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		iso2  string
+		name  string
+		match bool
+	}{
+		{iso2: "PL", name: "POLAND", match: true},
+		{iso2: "PL", name: "Republic of Poland", match: true},
+		{iso2: "PL", name: "POLSKA", match: false},
+		{iso2: "PL", name: "", match: false},
+		{iso2: "TR", name: "TURKIYE", match: true},
+		{iso2: "CI", name: "COTE D'IVOIRE", match: true},
+		{iso2: "KR", name: "South Korea", match: true},
+		{iso2: "KR", name: "REPUBLIC OF KOREA", match: true},
+		{iso2: "BO", name: "Bolivia", match: true},
+		{iso2: "MC", name: "MALTA", match: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.iso2+" "+tt.name, func(t *testing.T) {
+			c, ok := ByAlpha2(tt.iso2)
+			require.True(t, ok)
+			assert.Equal(t, tt.match, c.Matches(tt.name))
+		})
+	}
+}
+
+func TestFoldAccents(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "Kraków", want: "KRAKOW"},
+		{name: "Iași", want: "IASI"},
+		{name: "Şişli", want: "SISLI"},
+		{name: "Rīga", want: "RIGA"},
+		{name: "Đakovo", want: "DAKOVO"},
+		{name: "İzmir", want: "IZMIR"},
+		{name: "Diyarbakır", want: "DIYARBAKIR"},
+		{name: "Łódź", want: "LODZ"},
+		{name: "Tórshavn", want: "TORSHAVN"},
+		{name: "Ærøskøbing", want: "AEROSKOBING"},
+		{name: "Gießen", want: "GIESSEN"},
+		{name: "Hà Nội", want: "HA NOI"},
+		{name: "서울", want: "서울"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FoldAccents(tt.name))
+		})
+	}
+}
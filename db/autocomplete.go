@@ -0,0 +1,148 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/grysj/remitly-api/country"
+	"github.com/redis/go-redis/v9"
+)
+
+// Both indexes are sorted sets with every score at zero, so members are
+// ordered lexicographically and a prefix is one ZRANGEBYLEX.
+const (
+	swiftLexKey = "idx:lex:swift"
+	nameLexKey  = "idx:lex:name"
+)
+
+// lexSeparator ends the bank or town name in members of lex-ordered
+// indexes, which are "<NAME>\x00<SWIFT>". It sorts before any character of a name, so the
+// banks called exactly "ING" come before "ING BANK".
+const lexSeparator = "\x00"
+
+const (
+	DefaultAutocompleteLimit = 10
+	MaxAutocompleteLimit     = 50
+)
+
+var ErrEmptyPrefix = errors.New("autocomplete prefix is empty")
+
+type AutocompleteParams struct {
+	Prefix string
+	Limit  int
+}
+
+type Suggestion struct {
+	Swift      string `json:"swiftCode" redis:"-"`
+	Name       string `json:"bankName" redis:"bankName"`
+	ISO2       string `json:"countryISO2" redis:"countryISO2"`
+	Headquater bool   `json:"isHeadquater" redis:"isHeadquater"`
+}
+
+type AutocompleteResult struct {
+	SwiftCodes []Suggestion `json:"swiftCodes"`
+	BankNames  []Suggestion `json:"bankNames"`
+}
+
+// lexName is the form of a bank name stored in the name index: upper case,
+// without accents and with single spaces.
+func lexName(name string) string {
+	folded := strings.Join(strings.Fields(country.FoldAccents(name)), " ")
+	if folded != "" && strings.HasSuffix(name, " ") {
+		folded += " "
+	}
+	return folded
+}
+
+func nameLexMember(bank Bank) (string, bool) {
+	name := lexName(strings.TrimSpace(bank.Name))
+	if name == "" {
+		return "", false
+	}
+	return name + lexSeparator + bank.Swift, true
+}
+
+// Autocomplete returns the first codes starting with the prefix and the
+// first banks whose name starts with it, each in lexicographic order.
+func (s *RedisStore) Autocomplete(params AutocompleteParams) (*AutocompleteResult, error) {
+	codePrefix := strings.ToUpper(strings.TrimSpace(params.Prefix))
+	namePrefix := lexName(strings.TrimLeft(params.Prefix, " "))
+	if codePrefix == "" || namePrefix == "" {
+		return nil, ErrEmptyPrefix
+	}
+	limit := params.Limit
+	if limit <= 0 {
+		limit = DefaultAutocompleteLimit
+	}
+	limit = min(limit, MaxAutocompleteLimit)
+
+	ctx := context.Background()
+	ks, err := s.active(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pipe := s.client.Pipeline()
+	codes := pipe.ZRangeByLex(ctx, ks.swiftLex(), &redis.ZRangeBy{
+		Min:   "[" + codePrefix,
+		Max:   "[" + codePrefix + "\xff",
+		Count: int64(limit),
+	})
+	names := pipe.ZRangeByLex(ctx, ks.nameLex(), &redis.ZRangeBy{
+		Min:   "[" + namePrefix,
+		Max:   "[" + namePrefix + "\xff",
+		Count: int64(limit),
+	})
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to autocomplete %q: %w", params.Prefix, err)
+	}
+
+	nameSwifts := make([]string, 0, len(names.Val()))
+	for _, member := range names.Val() {
+		if _, swift, ok := strings.Cut(member, lexSeparator); ok {
+			nameSwifts = append(nameSwifts, swift)
+		}
+	}
+
+	pipe = s.client.Pipeline()
+	lookups := make(map[string]*redis.SliceCmd)
+	for _, swift := range append(codes.Val(), nameSwifts...) {
+		if _, ok := lookups[swift]; !ok {
+			lookups[swift] = pipe.HMGet(ctx, ks.bank(swift), "bankName", "countryISO2", "isHeadquater")
+		}
+	}
+	if len(lookups) > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, fmt.Errorf("failed to get bank data: %w", err)
+		}
+	}
+
+	suggestions := func(swifts []string) ([]Suggestion, error) {
+		found := make([]Suggestion, 0, len(swifts))
+		for _, swift := range swifts {
+			cmd := lookups[swift]
+			if cmd.Val()[1] == nil {
+				// deleted since the index was read
+				continue
+			}
+			var suggestion Suggestion
+			if err := cmd.Scan(&suggestion); err != nil {
+				return nil, fmt.Errorf("failed to parse bank %s: %w", swift, err)
+			}
+			suggestion.Swift = swift
+			found = append(found, suggestion)
+		}
+		return found, nil
+	}
+
+	result := &AutocompleteResult{}
+	if result.SwiftCodes, err = suggestions(codes.Val()); err != nil {
+		return nil, err
+	}
+	if result.BankNames, err = suggestions(nameSwifts); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,97 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/grysj/remitly-api/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutocomplete(t *testing.T) {
+	require.NoError(t, testStore.client.FlushDB(testCtx).Err())
+	_, err := testStore.ReloadDataset(ReloadDatasetParams{Rows: []parser.CsvRow{
+		{ISO2: "PL", Swift: "INGBPLPWXXX", Name: "ING BANK SLASKI S.A.", Country: "POLAND"},
+		{ISO2: "PL", Swift: "INGBPLPWCUS", Name: "ING BANK SLASKI S.A.", Country: "POLAND"},
+		{ISO2: "NL", Swift: "INGBNL2AXXX", Name: "ING", Country: "NETHERLANDS"},
+		{ISO2: "MT", Swift: "INDUMTMTXXX", Name: "INDUSTRIAL BANK", Country: "MALTA"},
+		{ISO2: "PL", Swift: "BREXPLPWMBK", Name: "mBank Łódź", Country: "POLAND"},
+	}})
+	require.NoError(t, err)
+
+	swifts := func(suggestions []Suggestion) []string {
+		codes := make([]string, 0, len(suggestions))
+		for _, suggestion := range suggestions {
+			codes = append(codes, suggestion.Swift)
+		}
+		return codes
+	}
+
+	tests := []struct {
+		name      string
+		params    AutocompleteParams
+		wantCodes []string
+		wantNames []string
+	}{
+		{
+			name:      "Code And Name Prefix",
+			params:    AutocompleteParams{Prefix: "ing"},
+			wantCodes: []string{"INGBNL2AXXX", "INGBPLPWCUS", "INGBPLPWXXX"},
+			wantNames: []string{"INGBNL2AXXX", "INGBPLPWCUS", "INGBPLPWXXX"},
+		},
+		{
+			name:      "Trailing Space Ends A Word",
+			params:    AutocompleteParams{Prefix: "ing "},
+			wantCodes: []string{"INGBNL2AXXX", "INGBPLPWCUS", "INGBPLPWXXX"},
+			wantNames: []string{"INGBPLPWCUS", "INGBPLPWXXX"},
+		},
+		{
+			name:      "Limit",
+			params:    AutocompleteParams{Prefix: "IN", Limit: 2},
+			wantCodes: []string{"INDUMTMTXXX", "INGBNL2AXXX"},
+			wantNames: []string{"INDUMTMTXXX", "INGBNL2AXXX"},
+		},
+		{
+			name:      "Accents In Prefix",
+			params:    AutocompleteParams{Prefix: "mbank łó"},
+			wantCodes: []string{},
+			wantNames: []string{"BREXPLPWMBK"},
+		},
+		{
+			name:      "Code Only",
+			params:    AutocompleteParams{Prefix: "INGBPLPWC"},
+			wantCodes: []string{"INGBPLPWCUS"},
+			wantNames: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := testStore.Autocomplete(tt.params)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCodes, swifts(result.SwiftCodes))
+			assert.Equal(t, tt.wantNames, swifts(result.BankNames))
+		})
+	}
+
+	t.Run("Empty Prefix", func(t *testing.T) {
+		_, err := testStore.Autocomplete(AutocompleteParams{Prefix: "  "})
+		assert.ErrorIs(t, err, ErrEmptyPrefix)
+	})
+
+	t.Run("Writes Keep The Index Current", func(t *testing.T) {
+		_, err := testStore.UpdateBank(UpdateBankParams{Bank: Bank{Swift: "INDUMTMTXXX", ISO2: "MT", Name: "BANK OF VALLETTA"}})
+		require.NoError(t, err)
+		result, err := testStore.Autocomplete(AutocompleteParams{Prefix: "indu"})
+		require.NoError(t, err)
+		assert.Empty(t, result.BankNames)
+		require.Len(t, result.SwiftCodes, 1)
+		assert.Equal(t, "BANK OF VALLETTA", result.SwiftCodes[0].Name)
+
+		require.NoError(t, testStore.DeleteBankFromDB(DeleteBankParams{Swift: "INDUMTMTXXX"}))
+		result, err = testStore.Autocomplete(AutocompleteParams{Prefix: "indu"})
+		require.NoError(t, err)
+		assert.Empty(t, result.SwiftCodes)
+	})
+	require.NoError(t, testStore.client.FlushDB(testCtx).Err())
+}
@@ -0,0 +1,290 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/grysj/remitly-api/parser"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	datasetsKey       = "dataset:versions"
+	datasetMetaPrefix = "dataset:meta:"
+)
+
+// defaultDatasetRetention is the number of dataset versions kept for
+// rollback when NewRedisStoreParams does not set one.
+const defaultDatasetRetention = 5
+
+var ErrDatasetNotFound = errors.New("dataset version not found")
+
+type DatasetVersion struct {
+	ID         string `json:"id" redis:"id"`
+	ImportedAt string `json:"importedAt" redis:"importedAt"`
+	Source     string `json:"source" redis:"source"`
+	Checksum   string `json:"checksum" redis:"checksum"`
+	Rows       int    `json:"rows" redis:"rows"`
+	Active     bool   `json:"active" redis:"-"`
+}
+
+type ReloadDatasetParams struct {
+	Rows   []parser.CsvRow
+	Source string
+}
+
+func datasetMetaKey(version string) string {
+	return datasetMetaPrefix + version
+}
+
+// ReloadDataset writes rows into a new dataset version and switches readers
+// to it with a single SET. Until the switch, readers keep seeing the old
+// data in full. The newest versions are kept for rollback; older ones are
+// dropped. When the active version was imported from the same source with
+// the same checksum, nothing is written and the active version is returned,
+// so changes made through the API since that import are kept.
+func (s *RedisStore) ReloadDataset(params ReloadDatasetParams) (*DatasetVersion, error) {
+	ctx := context.Background()
+
+	previous, err := activeKeyspace(ctx, s.client)
+	if err != nil {
+		return nil, err
+	}
+
+	// rows naming the same code, such as its BIC8 and BIC11 forms, collapse
+	// into the last one
+	banks := make(map[string]Bank, len(params.Rows))
+	for _, row := range params.Rows {
+		bank := formatBank(bankFromRow(row))
+		banks[bank.Swift] = bank
+	}
+	checksum := datasetChecksum(banks)
+
+	if previous.version != "" {
+		var current DatasetVersion
+		if err := s.client.HGetAll(ctx, datasetMetaKey(previous.version)).Scan(&current); err != nil {
+			return nil, fmt.Errorf("failed to read dataset version %s: %w", previous.version, err)
+		}
+		if current.Checksum == checksum && current.Source == params.Source {
+			current.ID = previous.version
+			current.Active = true
+			return &current, nil
+		}
+	}
+
+	seq, err := s.client.Incr(ctx, versionSeqKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate dataset version: %w", err)
+	}
+	staging := keyspace{version: strconv.FormatInt(seq, 10)}
+
+	swifts := make([]string, 0, len(banks))
+	for swift := range banks {
+		swifts = append(swifts, swift)
+	}
+	sort.Strings(swifts)
+	ordered := make([]Bank, 0, len(swifts))
+	for _, swift := range swifts {
+		ordered = append(ordered, banks[swift])
+	}
+
+	if err := s.writeDataset(ctx, staging, ordered); err != nil {
+		if dropErr := s.dropKeyspace(ctx, staging); dropErr != nil {
+			return nil, fmt.Errorf("%w (cleanup failed: %v)", err, dropErr)
+		}
+		return nil, err
+	}
+
+	meta := DatasetVersion{
+		ID:         staging.version,
+		ImportedAt: time.Now().UTC().Format(time.RFC3339),
+		Source:     params.Source,
+		Checksum:   checksum,
+		Rows:       len(banks),
+		Active:     true,
+	}
+
+	pipe := s.client.TxPipeline()
+	pipe.HSet(ctx, datasetMetaKey(meta.ID), meta)
+	pipe.ZAdd(ctx, datasetsKey, redis.Z{Score: float64(seq), Member: meta.ID})
+	pipe.Set(ctx, activeVersionKey, meta.ID, 0)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to activate dataset version %s: %w", meta.ID, err)
+	}
+
+	if err := s.recordDatasetChanges(ctx, previous, staging, SourceImport); err != nil {
+		return &meta, fmt.Errorf("dataset version %s is active but its changes were not recorded: %w", meta.ID, err)
+	}
+	if previous.version == "" {
+		if err := s.dropKeyspace(ctx, previous); err != nil {
+			return &meta, fmt.Errorf("dataset version %s is active but the unversioned data was not removed: %w", meta.ID, err)
+		}
+	}
+	if err := s.pruneDatasets(ctx); err != nil {
+		return &meta, fmt.Errorf("dataset version %s is active but old versions were not removed: %w", meta.ID, err)
+	}
+	return &meta, nil
+}
+
+// pruneDatasets drops the oldest versions beyond the retention limit. The
+// active version is never dropped.
+func (s *RedisStore) pruneDatasets(ctx context.Context) error {
+	versions, err := s.client.ZRevRange(ctx, datasetsKey, 0, -1).Result()
+	if err != nil {
+		return fmt.Errorf("failed to list dataset versions: %w", err)
+	}
+	active, err := activeKeyspace(ctx, s.client)
+	if err != nil {
+		return err
+	}
+
+	kept := 0
+	for _, version := range versions {
+		if kept < s.retention || version == active.version {
+			kept++
+			continue
+		}
+		if err := s.dropKeyspace(ctx, keyspace{version: version}); err != nil {
+			return err
+		}
+		pipe := s.client.TxPipeline()
+		pipe.Del(ctx, datasetMetaKey(version))
+		pipe.ZRem(ctx, datasetsKey, version)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("failed to remove dataset version %s: %w", version, err)
+		}
+	}
+	return nil
+}
+
+// ListDatasets returns the retained dataset versions, newest first.
+func (s *RedisStore) ListDatasets() ([]DatasetVersion, error) {
+	ctx := context.Background()
+
+	versions, err := s.client.ZRevRange(ctx, datasetsKey, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dataset versions: %w", err)
+	}
+	active, err := activeKeyspace(ctx, s.client)
+	if err != nil {
+		return nil, err
+	}
+
+	pipe := s.client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(versions))
+	for i, version := range versions {
+		cmds[i] = pipe.HGetAll(ctx, datasetMetaKey(version))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to read dataset versions: %w", err)
+	}
+
+	datasets := make([]DatasetVersion, 0, len(versions))
+	for i, cmd := range cmds {
+		var dataset DatasetVersion
+		if err := cmd.Scan(&dataset); err != nil {
+			return nil, fmt.Errorf("failed to read dataset version %s: %w", versions[i], err)
+		}
+		dataset.ID = versions[i]
+		dataset.Active = dataset.ID == active.version
+		datasets = append(datasets, dataset)
+	}
+	return datasets, nil
+}
+
+// ActivateDataset switches readers back (or forward) to a retained version.
+// A non-nil version with an error means the switch happened but the history
+// of the affected codes was not fully recorded.
+func (s *RedisStore) ActivateDataset(version string) (*DatasetVersion, error) {
+	ctx := context.Background()
+
+	var dataset DatasetVersion
+	var previous keyspace
+	err := s.client.Watch(ctx, func(tx *redis.Tx) error {
+		var err error
+		previous, err = activeKeyspace(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		cmd := tx.HGetAll(ctx, datasetMetaKey(version))
+		if err := cmd.Err(); err != nil {
+			return fmt.Errorf("failed to read dataset version %s: %w", version, err)
+		}
+		if len(cmd.Val()) == 0 {
+			return ErrDatasetNotFound
+		}
+		if err := cmd.Scan(&dataset); err != nil {
+			return fmt.Errorf("failed to read dataset version %s: %w", version, err)
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, activeVersionKey, version, 0)
+			return nil
+		})
+		return err
+	}, datasetMetaKey(version), activeVersionKey)
+	if err != nil {
+		if errors.Is(err, ErrDatasetNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to activate dataset version %s: %w", version, err)
+	}
+
+	dataset.ID = version
+	dataset.Active = true
+
+	if previous.version != version {
+		if err := s.recordDatasetChanges(ctx, previous, keyspace{version: version}, SourceRollback); err != nil {
+			return &dataset, fmt.Errorf("dataset version %s is active but its changes were not recorded: %w", version, err)
+		}
+	}
+	return &dataset, nil
+}
+
+// ActiveDataset returns the version reads are currently served from, or an
+// empty string for data loaded before datasets were versioned.
+func (s *RedisStore) ActiveDataset() (string, error) {
+	ks, err := s.active(context.Background())
+	if err != nil {
+		return "", err
+	}
+	return ks.version, nil
+}
+
+// AtDataset returns a store whose reads are served from the given version
+// even if another version is activated meanwhile. Writes always go to the
+// active version.
+func (s *RedisStore) AtDataset(version string) *Store {
+	pinned := *s
+	pinned.pinned = version
+	return &Store{DBQuerier: &pinned}
+}
+
+// datasetChecksum hashes the stored records in SWIFT code order, so the same
+// data imported from differently ordered or formatted files has the same
+// checksum.
+func datasetChecksum(banks map[string]Bank) string {
+	swifts := make([]string, 0, len(banks))
+	for swift := range banks {
+		swifts = append(swifts, swift)
+	}
+	sort.Strings(swifts)
+
+	hash := sha256.New()
+	for _, swift := range swifts {
+		bank := banks[swift]
+		for _, field := range []string{bank.Swift, bank.ISO2, bank.Name, bank.Type, bank.Address, bank.Town, bank.Country, bank.Timezone} {
+			hash.Write([]byte(field))
+			hash.Write([]byte{0x1f})
+		}
+		hash.Write([]byte{0x1e})
+	}
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil))
+}
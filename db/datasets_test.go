@@ -0,0 +1,119 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/grysj/remitly-api/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatasetVersions(t *testing.T) {
+	require.NoError(t, testStore.client.FlushDB(testCtx).Err())
+
+	store := *testStore
+	store.retention = 2
+
+	rows := func(name string) []parser.CsvRow {
+		return []parser.CsvRow{
+			{ISO2: "AL", Swift: "AAISALTRXXX", Name: name, Country: "ALBANIA"},
+			{ISO2: "AL", Swift: "AAISALTR001", Name: name, Country: "ALBANIA"},
+		}
+	}
+
+	for i, name := range []string{"FIRST", "SECOND", "THIRD"} {
+		dataset, err := store.ReloadDataset(ReloadDatasetParams{Rows: rows(name), Source: name + ".csv"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1", "2", "3"}[i], dataset.ID)
+	}
+
+	datasets, err := store.ListDatasets()
+	require.NoError(t, err)
+	require.Len(t, datasets, 2, "only the newest versions are retained")
+	assert.Equal(t, "3", datasets[0].ID)
+	assert.Equal(t, "THIRD.csv", datasets[0].Source)
+	assert.Equal(t, 2, datasets[0].Rows)
+	assert.True(t, datasets[0].Active)
+	assert.NotEmpty(t, datasets[0].ImportedAt)
+	assert.Regexp(t, "^sha256:[0-9a-f]{64}$", datasets[0].Checksum)
+	assert.Equal(t, "2", datasets[1].ID)
+	assert.False(t, datasets[1].Active)
+	assert.NotEqual(t, datasets[0].Checksum, datasets[1].Checksum)
+
+	dropped, err := store.client.Keys(testCtx, "ds:1:*").Result()
+	require.NoError(t, err)
+	assert.Empty(t, dropped)
+
+	pinned := store.AtDataset("3")
+
+	activated, err := store.ActivateDataset("2")
+	require.NoError(t, err)
+	assert.Equal(t, "SECOND.csv", activated.Source)
+
+	version, err := store.ActiveDataset()
+	require.NoError(t, err)
+	assert.Equal(t, "2", version)
+
+	bank, err := store.GetBankFromSwift("AAISALTRXXX")
+	require.NoError(t, err)
+	require.NotNil(t, bank)
+	assert.Equal(t, "SECOND", bank.Name)
+
+	bank, err = pinned.GetBankFromSwift("AAISALTRXXX")
+	require.NoError(t, err)
+	require.NotNil(t, bank)
+	assert.Equal(t, "THIRD", bank.Name, "a pinned store keeps reading its version")
+
+	_, err = store.ActivateDataset("1")
+	assert.ErrorIs(t, err, ErrDatasetNotFound)
+
+	// reloading after a rollback starts a new version on top of the newest one
+	dataset, err := store.ReloadDataset(ReloadDatasetParams{Rows: rows("FOURTH"), Source: "FOURTH.csv"})
+	require.NoError(t, err)
+	assert.Equal(t, "4", dataset.ID)
+
+	datasets, err = store.ListDatasets()
+	require.NoError(t, err)
+	ids := make([]string, 0, len(datasets))
+	for _, dataset := range datasets {
+		ids = append(ids, dataset.ID)
+	}
+	assert.Equal(t, []string{"4", "3"}, ids)
+}
+
+func TestReloadUnchangedDataset(t *testing.T) {
+	require.NoError(t, testStore.client.FlushDB(testCtx).Err())
+
+	rows := []parser.CsvRow{{ISO2: "AL", Swift: "AAISALTRXXX", Name: "UNITED BANK OF ALBANIA SH.A", Country: "ALBANIA"}}
+	first, err := testStore.ReloadDataset(ReloadDatasetParams{Rows: rows, Source: "codes.csv"})
+	require.NoError(t, err)
+	_, err = testStore.AddBankToDB(Bank{ISO2: "BG", Swift: "ABIEBGS1XXX", Name: "ABV INVESTMENTS LTD", Country: "BULGARIA"})
+	require.NoError(t, err)
+
+	again, err := testStore.ReloadDataset(ReloadDatasetParams{Rows: rows, Source: "codes.csv"})
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, again.ID, "the same file from the same source is not imported again")
+	assert.True(t, again.Active)
+	bank, err := testStore.GetBank("ABIEBGS1XXX")
+	require.NoError(t, err)
+	assert.NotNil(t, bank, "codes added through the API are kept")
+
+	moved, err := testStore.ReloadDataset(ReloadDatasetParams{Rows: rows, Source: "other.csv"})
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ID, moved.ID)
+}
+
+func TestDatasetChecksum(t *testing.T) {
+	a := Bank{Swift: "AAISALTRXXX", ISO2: "AL", Name: "UNITED BANK OF ALBANIA SH.A"}
+	b := Bank{Swift: "ABIEBGS1XXX", ISO2: "BG", Name: "ABV INVESTMENTS LTD"}
+
+	assert.Equal(t,
+		datasetChecksum(map[string]Bank{a.Swift: a, b.Swift: b}),
+		datasetChecksum(map[string]Bank{b.Swift: b, a.Swift: a}))
+
+	changed := b
+	changed.Name = "ABV INVESTMENTS"
+	assert.NotEqual(t,
+		datasetChecksum(map[string]Bank{a.Swift: a, b.Swift: b}),
+		datasetChecksum(map[string]Bank{a.Swift: a, b.Swift: changed}))
+}
@@ -3,20 +3,40 @@ package db
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/grysj/remitly-api/bic"
 	"github.com/grysj/remitly-api/parser"
 	"github.com/redis/go-redis/v9"
 )
 
 type DBQuerier interface {
 	AddBanksFromCSV(rows []parser.CsvRow) error
-	AddBankToDB(bank Bank) error
+	ReloadDataset(params ReloadDatasetParams) (*DatasetVersion, error)
+	ListDatasets() ([]DatasetVersion, error)
+	ActivateDataset(version string) (*DatasetVersion, error)
+	ActiveDataset() (string, error)
+	AtDataset(version string) *Store
+	WithActor(actor string) *Store
+	GetBankHistory(swift string) ([]HistoryEvent, error)
+	ListDeletedBanks() ([]DeletedBank, error)
+	RestoreBank(swift string) ([]Bank, error)
+	DiffBanks(rows []parser.CsvRow) (*DatasetDelta, error)
+	ApplyDelta(delta *DatasetDelta) error
+	CheckConsistency(params CheckConsistencyParams) (*ConsistencyReport, error)
+	AddBankToDB(bank Bank) (*Bank, error)
+	UpdateBank(params UpdateBankParams) (*Bank, error)
+	GetBank(swift string) (*Bank, error)
 	DeleteBankFromDB(bank DeleteBankParams) error
 	GetBanksByISO2(iso2 string) ([]GetBankByIsoResult, error)
+	GetTownsByISO2(iso2 string) ([]TownCount, error)
+	GetBanksByTown(iso2, town string) ([]GetBankByIsoResult, error)
 	GetBankBranches(swift string) ([]GetBranchesBySwiftResult, error)
-	DeleteBanksBySwiftPrefix(swiftPrefix string) error
+	DeleteBanksBySwiftPrefix(code bic.BIC) error
 	GetCountryNameByISO2(iso2 string) (string, error)
 	GetBankFromSwift(swift string) (*GetBankBySwiftResult, error)
+	SearchBanks(params SearchBanksParams) (*SearchResult, error)
+	Autocomplete(params AutocompleteParams) (*AutocompleteResult, error)
 	CleanDB(ctx context.Context) error
 	CloseConnection() error
 }
@@ -26,7 +46,12 @@ type Store struct {
 }
 
 type RedisStore struct {
-	client *redis.Client
+	client    *redis.Client
+	retention int
+	pinned    string
+	actor     string
+
+	deletedRetention time.Duration
 }
 
 type NewRedisStoreParams struct {
@@ -34,6 +59,9 @@ type NewRedisStoreParams struct {
 	RedisPort     string
 	RedisPassword string
 	RedisDB       int
+
+	DatasetRetention int
+	DeletedRetention time.Duration
 }
 
 func NewRedisStore(cfg NewRedisStoreParams) (*Store, error) {
@@ -47,8 +75,22 @@ func NewRedisStore(cfg NewRedisStoreParams) (*Store, error) {
 		return nil, fmt.Errorf("redis connection failed: %w", err)
 	}
 
+	retention := cfg.DatasetRetention
+	if retention <= 0 {
+		retention = defaultDatasetRetention
+	}
+
+	deletedRetention := cfg.DeletedRetention
+	if deletedRetention <= 0 {
+		deletedRetention = defaultDeletedRetention
+	}
+
 	return &Store{
-		DBQuerier: &RedisStore{client: client},
+		DBQuerier: &RedisStore{
+			client:           client,
+			retention:        retention,
+			deletedRetention: deletedRetention,
+		},
 	}, nil
 }
 
@@ -0,0 +1,254 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	deletedKeyPrefix = "deleted:"
+	deletedIndexKey  = "idx:deleted"
+	cascadeKeyPrefix = "cascade:"
+)
+
+// defaultDeletedRetention is how long deleted codes can be restored when
+// NewRedisStoreParams does not set a retention.
+const defaultDeletedRetention = 30 * 24 * time.Hour
+
+var ErrBankNotDeleted = errors.New("no deleted bank with this SWIFT code")
+
+// DeletedBank is a soft-deleted record. DeletedWith names the headquarters
+// whose deletion removed the record together with its branches.
+type DeletedBank struct {
+	Bank
+	DeletedAt   string `json:"deletedAt"`
+	DeletedBy   string `json:"deletedBy"`
+	DeletedWith string `json:"deletedWith,omitempty"`
+	PurgeAt     string `json:"purgeAt"`
+}
+
+type deletedMeta struct {
+	DeletedAt   int64  `redis:"deletedAt"`
+	DeletedBy   string `redis:"deletedBy"`
+	DeletedWith string `redis:"deletedWith"`
+}
+
+// softDelete removes a bank from every read path and keeps its record for
+// restore until the retention period ends, when Redis expires it. Records
+// are kept in the dataset version they were deleted from, so they cannot
+// be restored while another version is active.
+func (s *RedisStore) softDelete(ctx context.Context, pipe redis.Pipeliner, ks keyspace, bank Bank, cascade string, now time.Time) {
+	removeBank(ctx, pipe, ks, bank)
+
+	purgeAt := now.Add(s.deletedRetention)
+	deletedKey := ks.deleted(bank.Swift)
+	pipe.Del(ctx, deletedKey)
+	pipe.HSet(ctx, deletedKey, &bank)
+	pipe.HSet(ctx, deletedKey, &deletedMeta{
+		DeletedAt:   now.UnixMilli(),
+		DeletedBy:   s.actorName(),
+		DeletedWith: cascade,
+	})
+	pipe.PExpireAt(ctx, deletedKey, purgeAt)
+	pipe.ZAdd(ctx, ks.deletedIndex(), redis.Z{Score: float64(now.UnixMilli()), Member: bank.Swift})
+	if cascade != "" {
+		pipe.SAdd(ctx, ks.cascade(cascade), bank.Swift)
+		pipe.PExpireAt(ctx, ks.cascade(cascade), purgeAt)
+	}
+	s.recordChange(ctx, pipe, ks, SourceAPI, &bank, nil)
+}
+
+// purgeDeleted permanently removes deleted records older than the retention
+// period. Records expire on their own; this drops their index entries and
+// records written before they were given an expiry.
+func (s *RedisStore) purgeDeleted(ctx context.Context, ks keyspace) error {
+	cutoff := time.Now().Add(-s.deletedRetention).UnixMilli()
+	expired, err := s.client.ZRangeByScore(ctx, ks.deletedIndex(), &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(cutoff, 10),
+	}).Result()
+	if err != nil {
+		return fmt.Errorf("failed to list expired deleted banks: %w", err)
+	}
+
+	for _, swift := range expired {
+		cascade, err := s.client.HGet(ctx, ks.deleted(swift), "deletedWith").Result()
+		if err != nil && err != redis.Nil {
+			return fmt.Errorf("failed to read deleted bank %s: %w", swift, err)
+		}
+		pipe := s.client.TxPipeline()
+		pipe.Del(ctx, ks.deleted(swift))
+		pipe.ZRem(ctx, ks.deletedIndex(), swift)
+		if cascade != "" {
+			pipe.SRem(ctx, ks.cascade(cascade), swift)
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("failed to purge deleted bank %s: %w", swift, err)
+		}
+	}
+	return nil
+}
+
+// purgeActiveDeleted runs purgeDeleted on the dataset version the store
+// reads from.
+func (s *RedisStore) purgeActiveDeleted(ctx context.Context) error {
+	ks, err := s.active(ctx)
+	if err != nil {
+		return err
+	}
+	return s.purgeDeleted(ctx, ks)
+}
+
+// ListDeletedBanks returns the deleted codes that can still be restored,
+// most recently deleted first.
+func (s *RedisStore) ListDeletedBanks() ([]DeletedBank, error) {
+	ctx := context.Background()
+	ks, err := s.active(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.purgeDeleted(ctx, ks); err != nil {
+		return nil, err
+	}
+
+	swifts, err := s.client.ZRevRange(ctx, ks.deletedIndex(), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deleted banks: %w", err)
+	}
+
+	pipe := s.client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(swifts))
+	for i, swift := range swifts {
+		cmds[i] = pipe.HGetAll(ctx, ks.deleted(swift))
+	}
+	if len(swifts) > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, fmt.Errorf("failed to get deleted banks: %w", err)
+		}
+	}
+
+	deleted := make([]DeletedBank, 0, len(swifts))
+	for i, cmd := range cmds {
+		if len(cmd.Val()) == 0 {
+			continue
+		}
+		bank, err := scanDeletedBank(cmd, s.deletedRetention)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse deleted bank %s: %w", swifts[i], err)
+		}
+		deleted = append(deleted, bank)
+	}
+	return deleted, nil
+}
+
+func scanDeletedBank(cmd *redis.MapStringStringCmd, retention time.Duration) (DeletedBank, error) {
+	var bank Bank
+	if err := cmd.Scan(&bank); err != nil {
+		return DeletedBank{}, err
+	}
+	var meta deletedMeta
+	if err := cmd.Scan(&meta); err != nil {
+		return DeletedBank{}, err
+	}
+	deletedAt := time.UnixMilli(meta.DeletedAt).UTC()
+	return DeletedBank{
+		Bank:        bank,
+		DeletedAt:   deletedAt.Format(time.RFC3339),
+		DeletedBy:   meta.DeletedBy,
+		DeletedWith: meta.DeletedWith,
+		PurgeAt:     deletedAt.Add(retention).Format(time.RFC3339),
+	}, nil
+}
+
+// RestoreBank brings back a deleted code with all its index entries. Restoring
+// a headquarters also restores the branches deleted in the same cascade.
+func (s *RedisStore) RestoreBank(swift string) ([]Bank, error) {
+	ctx := context.Background()
+
+	active, err := activeKeyspace(ctx, s.client)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.purgeDeleted(ctx, active); err != nil {
+		return nil, err
+	}
+
+	var restored []Bank
+	err = s.update(ctx, func(tx *redis.Tx, ks keyspace) error {
+		restored = nil
+		if err := tx.Watch(ctx, ks.cascade(swift), ks.deleted(swift)).Err(); err != nil {
+			return fmt.Errorf("failed to watch deleted bank: %w", err)
+		}
+		members, err := tx.SMembers(ctx, ks.cascade(swift)).Result()
+		if err != nil {
+			return fmt.Errorf("failed to get cascade members: %w", err)
+		}
+		swifts := append([]string{swift}, members...)
+
+		cascades := make(map[string]string, len(swifts))
+		for _, code := range swifts {
+			cmd := tx.HGetAll(ctx, ks.deleted(code))
+			if err := cmd.Err(); err != nil {
+				return fmt.Errorf("failed to get deleted bank %s: %w", code, err)
+			}
+			if len(cmd.Val()) == 0 {
+				continue
+			}
+			bank, err := scanDeletedBank(cmd, s.deletedRetention)
+			if err != nil {
+				return fmt.Errorf("failed to parse deleted bank %s: %w", code, err)
+			}
+			if err := tx.Watch(ctx, ks.bank(code)).Err(); err != nil {
+				return fmt.Errorf("failed to watch bank %s: %w", code, err)
+			}
+			exists, err := tx.Exists(ctx, ks.bank(code)).Result()
+			if err != nil {
+				return fmt.Errorf("failed to check bank %s: %w", code, err)
+			}
+			if exists > 0 {
+				return fmt.Errorf("%s: %w", code, ErrBankExists)
+			}
+			bank.Swift = code
+			restored = append(restored, bank.Bank)
+			cascades[code] = bank.DeletedWith
+		}
+		if len(restored) == 0 {
+			return ErrBankNotDeleted
+		}
+
+		revision, err := s.nextRevisions(ctx, len(restored))
+		if err != nil {
+			return err
+		}
+		for i := range restored {
+			restored[i].Revision = revision + int64(i)
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, bank := range restored {
+				writeBank(ctx, pipe, ks, bank)
+				pipe.Del(ctx, ks.deleted(bank.Swift))
+				pipe.ZRem(ctx, ks.deletedIndex(), bank.Swift)
+				if cascade := cascades[bank.Swift]; cascade != "" {
+					pipe.SRem(ctx, ks.cascade(cascade), bank.Swift)
+				}
+				s.recordEvent(ctx, pipe, ks, bank.Swift, ChangeRestore, SourceAPI, nil, &bank)
+			}
+			pipe.Del(ctx, ks.cascade(swift))
+			return nil
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(restored, func(i, j int) bool { return restored[i].Swift < restored[j].Swift })
+	return restored, nil
+}
@@ -0,0 +1,219 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grysj/remitly-api/bic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestoreBank(t *testing.T) {
+	banks := []Bank{
+		{ISO2: "CL", Swift: "BCHICLRMXXX", Name: "BANCO DE CHILE", Country: "CHILE", Town: "SANTIAGO"},
+		{ISO2: "CL", Swift: "BCHICLRM001", Name: "BANCO DE CHILE BRANCH 1", Country: "CHILE", Town: "ARICA"},
+		{ISO2: "CL", Swift: "BCHICLRM002", Name: "BANCO DE CHILE BRANCH 2", Country: "CHILE", Town: "VINA DEL MAR"},
+	}
+
+	setup := func(t *testing.T) {
+		require.NoError(t, testStore.client.FlushDB(testCtx).Err())
+		for _, bank := range banks {
+			_, err := testStore.AddBankToDB(bank)
+			require.NoError(t, err)
+		}
+	}
+
+	t.Run("cascade_is_restored_with_headquarters", func(t *testing.T) {
+		setup(t)
+		clerk := testStore.WithActor("clerk")
+		require.NoError(t, clerk.DeleteBanksBySwiftPrefix(bic.BIC{Institution: "BCHI", Country: "CL", Location: "RM"}))
+
+		bank, err := testStore.GetBankFromSwift("BCHICLRMXXX")
+		require.NoError(t, err)
+		assert.Nil(t, bank, "deleted codes are hidden")
+		chile, err := testStore.GetBanksByISO2("CL")
+		require.NoError(t, err)
+		assert.Empty(t, chile)
+
+		deleted, err := testStore.ListDeletedBanks()
+		require.NoError(t, err)
+		require.Len(t, deleted, 3)
+		for _, bank := range deleted {
+			assert.Equal(t, "clerk", bank.DeletedBy)
+			assert.NotEmpty(t, bank.DeletedAt)
+			assert.NotEmpty(t, bank.PurgeAt)
+			if bank.Swift == "BCHICLRMXXX" {
+				assert.Empty(t, bank.DeletedWith)
+			} else {
+				assert.Equal(t, "BCHICLRMXXX", bank.DeletedWith)
+			}
+		}
+
+		restored, err := testStore.RestoreBank("BCHICLRMXXX")
+		require.NoError(t, err)
+		require.Len(t, restored, 3)
+		assert.Equal(t, "BCHICLRM001", restored[0].Swift)
+		assert.Equal(t, "BCHICLRMXXX", restored[2].Swift)
+
+		branches, err := testStore.GetBankBranches("BCHICLRMXXX")
+		require.NoError(t, err)
+		assert.Len(t, branches, 2)
+		chile, err = testStore.GetBanksByISO2("CL")
+		require.NoError(t, err)
+		assert.Len(t, chile, 3)
+
+		deleted, err = testStore.ListDeletedBanks()
+		require.NoError(t, err)
+		assert.Empty(t, deleted)
+
+		events, err := testStore.GetBankHistory("BCHICLRM001")
+		require.NoError(t, err)
+		require.Len(t, events, 3)
+		assert.Equal(t, ChangeRestore, events[2].Action)
+	})
+
+	t.Run("single_branch", func(t *testing.T) {
+		setup(t)
+		require.NoError(t, testStore.DeleteBankFromDB(DeleteBankParams{Swift: "BCHICLRM001"}))
+
+		_, err := testStore.RestoreBank("BCHICLRMXXX")
+		assert.ErrorIs(t, err, ErrBankNotDeleted)
+
+		restored, err := testStore.RestoreBank("BCHICLRM001")
+		require.NoError(t, err)
+		require.Len(t, restored, 1)
+		assert.Equal(t, "BANCO DE CHILE BRANCH 1", restored[0].Name)
+
+		branches, err := testStore.GetBankBranches("BCHICLRMXXX")
+		require.NoError(t, err)
+		assert.Len(t, branches, 2)
+	})
+
+	t.Run("recreated_code_is_not_overwritten", func(t *testing.T) {
+		setup(t)
+		require.NoError(t, testStore.DeleteBankFromDB(DeleteBankParams{Swift: "BCHICLRM002"}))
+		_, err := testStore.AddBankToDB(banks[2])
+		require.NoError(t, err)
+
+		_, err = testStore.RestoreBank("BCHICLRM002")
+		assert.ErrorIs(t, err, ErrBankExists)
+	})
+
+	t.Run("expired_records_are_purged", func(t *testing.T) {
+		setup(t)
+		store := *testStore
+		store.deletedRetention = time.Millisecond
+		require.NoError(t, store.DeleteBanksBySwiftPrefix(bic.BIC{Institution: "BCHI", Country: "CL", Location: "RM"}))
+		time.Sleep(5 * time.Millisecond)
+
+		deleted, err := store.ListDeletedBanks()
+		require.NoError(t, err)
+		assert.Empty(t, deleted)
+
+		_, err = store.RestoreBank("BCHICLRMXXX")
+		assert.ErrorIs(t, err, ErrBankNotDeleted)
+
+		keys, err := store.client.Keys(testCtx, "*deleted*").Result()
+		require.NoError(t, err)
+		assert.Empty(t, keys)
+		keys, err = store.client.Keys(testCtx, "cascade:*").Result()
+		require.NoError(t, err)
+		assert.Empty(t, keys)
+	})
+
+	t.Run("records_expire_without_a_read", func(t *testing.T) {
+		setup(t)
+		require.NoError(t, testStore.DeleteBanksBySwiftPrefix(bic.BIC{Institution: "BCHI", Country: "CL", Location: "RM"}))
+
+		for _, key := range []string{deletedKeyPrefix + "BCHICLRMXXX", deletedKeyPrefix + "BCHICLRM001", cascadeKeyPrefix + "BCHICLRMXXX"} {
+			ttl, err := testStore.client.PTTL(testCtx, key).Result()
+			require.NoError(t, err)
+			assert.Greater(t, ttl, time.Duration(0), key)
+			assert.LessOrEqual(t, ttl, testStore.deletedRetention, key)
+		}
+	})
+
+	t.Run("delete_purges_expired_records", func(t *testing.T) {
+		setup(t)
+		store := *testStore
+		store.deletedRetention = time.Millisecond
+		require.NoError(t, store.DeleteBankFromDB(DeleteBankParams{Swift: "BCHICLRM002"}))
+		time.Sleep(5 * time.Millisecond)
+
+		require.NoError(t, store.DeleteBankFromDB(DeleteBankParams{Swift: "BCHICLRM001"}))
+
+		indexed, err := store.client.ZRange(testCtx, deletedIndexKey, 0, -1).Result()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"BCHICLRM001"}, indexed)
+	})
+}
+
+func TestDeleteLastBankOfCountry(t *testing.T) {
+	require.NoError(t, testStore.client.FlushDB(testCtx).Err())
+	for _, bank := range []Bank{
+		{ISO2: "CL", Swift: "BCHICLRMXXX", Name: "BANCO DE CHILE", Country: "CHILE"},
+		{ISO2: "CL", Swift: "BCHICLRM001", Name: "BANCO DE CHILE BRANCH 1", Country: "CHILE"},
+		{ISO2: "MC", Swift: "AGRIMCM1XXX", Name: "CREDIT AGRICOLE MONACO", Country: "MONACO"},
+		{ISO2: "MC", Swift: "BAERMCMCXXX", Name: "BANK JULIUS BAER (MONACO) S.A.M.", Country: "MONACO"},
+	} {
+		_, err := testStore.AddBankToDB(bank)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, testStore.DeleteBankFromDB(DeleteBankParams{Swift: "AGRIMCM1XXX"}))
+	countries, err := testStore.client.HKeys(testCtx, countriesKey).Result()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"CL", "MC"}, countries, "MC still has a bank")
+
+	require.NoError(t, testStore.DeleteBankFromDB(DeleteBankParams{Swift: "BAERMCMCXXX"}))
+	require.NoError(t, testStore.DeleteBankFromDB(DeleteBankParams{Swift: "BCHICLRMXXX", Cascade: true}))
+	countries, err = testStore.client.HKeys(testCtx, countriesKey).Result()
+	require.NoError(t, err)
+	assert.Empty(t, countries)
+
+	report, err := testStore.CheckConsistency(CheckConsistencyParams{})
+	require.NoError(t, err)
+	assert.Empty(t, report.Issues)
+
+	_, err = testStore.RestoreBank("BCHICLRMXXX")
+	require.NoError(t, err)
+	countries, err = testStore.client.HKeys(testCtx, countriesKey).Result()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CL"}, countries)
+}
+
+func TestDeleteHeadquartersWithDanglingBranch(t *testing.T) {
+	require.NoError(t, testStore.client.FlushDB(testCtx).Err())
+	for _, bank := range []Bank{
+		{ISO2: "CL", Swift: "BCHICLRMXXX", Name: "BANCO DE CHILE", Country: "CHILE"},
+		{ISO2: "CL", Swift: "BCHICLRM001", Name: "BANCO DE CHILE BRANCH 1", Country: "CHILE"},
+	} {
+		_, err := testStore.AddBankToDB(bank)
+		require.NoError(t, err)
+	}
+	// a branch set member whose record is already gone
+	require.NoError(t, testStore.client.SAdd(testCtx, branchKeyPrefix+"BCHICLRM", "BCHICLRM002").Err())
+
+	require.NoError(t, testStore.DeleteBankFromDB(DeleteBankParams{Swift: "BCHICLRMXXX", Cascade: true}))
+
+	deleted, err := testStore.ListDeletedBanks()
+	require.NoError(t, err)
+	swifts := make([]string, 0, len(deleted))
+	for _, bank := range deleted {
+		swifts = append(swifts, bank.Swift)
+	}
+	assert.ElementsMatch(t, []string{"BCHICLRMXXX", "BCHICLRM001"}, swifts)
+
+	exists, err := testStore.client.Exists(testCtx, deletedKeyPrefix+"BCHICLRM002", branchKeyPrefix+"BCHICLRM").Result()
+	require.NoError(t, err)
+	assert.Zero(t, exists, "dangling member is dropped without a tombstone")
+
+	history, err := testStore.GetBankHistory("BCHICLRM002")
+	require.NoError(t, err)
+	assert.Empty(t, history)
+
+	restored, err := testStore.RestoreBank("BCHICLRMXXX")
+	require.NoError(t, err)
+	assert.Len(t, restored, 2)
+}
@@ -0,0 +1,268 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/grysj/remitly-api/parser"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrDeltaOutdated means a code the delta writes was changed in the store
+// after DiffBanks read it. Diff again and apply the new delta.
+var ErrDeltaOutdated = errors.New("store changed since the delta was computed")
+
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+type BankChange struct {
+	Swift   string        `json:"swiftCode"`
+	Changes []FieldChange `json:"changes"`
+	Before  Bank          `json:"-"`
+	After   Bank          `json:"-"`
+}
+
+type DatasetDelta struct {
+	Added     []Bank       `json:"added"`
+	Removed   []Bank       `json:"removed"`
+	Changed   []BankChange `json:"changed"`
+	Unchanged int          `json:"unchanged"`
+}
+
+func (d *DatasetDelta) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffBanks compares a parsed dataset with the banks currently in the store
+// without modifying anything. Later rows win when a code is repeated.
+func (s *RedisStore) DiffBanks(rows []parser.CsvRow) (*DatasetDelta, error) {
+	ctx := context.Background()
+
+	ks, err := s.active(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.allBanks(ctx, ks)
+	if err != nil {
+		return nil, err
+	}
+
+	incoming := make(map[string]Bank, len(rows))
+	for _, row := range rows {
+		bank := formatBank(bankFromRow(row))
+		incoming[bank.Swift] = bank
+	}
+
+	delta := &DatasetDelta{
+		Added:   make([]Bank, 0),
+		Removed: make([]Bank, 0),
+		Changed: make([]BankChange, 0),
+	}
+
+	for swift, bank := range incoming {
+		existing, ok := current[swift]
+		if !ok {
+			delta.Added = append(delta.Added, bank)
+			continue
+		}
+		changes := diffBankFields(existing, bank)
+		if len(changes) == 0 {
+			delta.Unchanged++
+			continue
+		}
+		delta.Changed = append(delta.Changed, BankChange{
+			Swift:   swift,
+			Changes: changes,
+			Before:  existing,
+			After:   bank,
+		})
+	}
+
+	for swift, bank := range current {
+		if _, ok := incoming[swift]; !ok {
+			delta.Removed = append(delta.Removed, bank)
+		}
+	}
+
+	sort.Slice(delta.Added, func(i, j int) bool { return delta.Added[i].Swift < delta.Added[j].Swift })
+	sort.Slice(delta.Removed, func(i, j int) bool { return delta.Removed[i].Swift < delta.Removed[j].Swift })
+	sort.Slice(delta.Changed, func(i, j int) bool { return delta.Changed[i].Swift < delta.Changed[j].Swift })
+
+	return delta, nil
+}
+
+// ApplyDelta writes a delta computed by DiffBanks in one transaction. It
+// fails with ErrDeltaOutdated, writing nothing, if any code it adds, changes
+// or removes is no longer stored as DiffBanks saw it.
+func (s *RedisStore) ApplyDelta(delta *DatasetDelta) error {
+	ctx := context.Background()
+	touched := make(map[string]bool)
+
+	var applied keyspace
+	err := s.update(ctx, func(tx *redis.Tx, ks keyspace) error {
+		applied = ks
+		if err := checkDelta(ctx, tx, ks, delta); err != nil {
+			return err
+		}
+		revision, err := s.nextRevisions(ctx, len(delta.Changed)+len(delta.Added))
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, bank := range delta.Removed {
+				removeBank(ctx, pipe, ks, bank)
+				s.recordChange(ctx, pipe, ks, SourceImport, &bank, nil)
+				touched[bank.ISO2] = true
+			}
+			for _, change := range delta.Changed {
+				if change.Before.ISO2 != change.After.ISO2 {
+					touched[change.Before.ISO2] = true
+				}
+				after := change.After
+				after.Revision = revision
+				revision++
+				removeBank(ctx, pipe, ks, change.Before)
+				writeBank(ctx, pipe, ks, after)
+				s.recordChange(ctx, pipe, ks, SourceImport, &change.Before, &after)
+			}
+			for _, bank := range delta.Added {
+				bank.Revision = revision
+				revision++
+				writeBank(ctx, pipe, ks, bank)
+				s.recordChange(ctx, pipe, ks, SourceImport, nil, &bank)
+			}
+			return nil
+		})
+		return err
+	})
+	if errors.Is(err, ErrDeltaOutdated) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to apply delta: %w", err)
+	}
+
+	return s.pruneCountries(ctx, applied, touched)
+}
+
+// checkDelta watches every code the delta writes and fails with
+// ErrDeltaOutdated unless each one is still stored exactly as the delta
+// expects: absent for added codes, unchanged for changed and removed ones.
+func checkDelta(ctx context.Context, tx *redis.Tx, ks keyspace, delta *DatasetDelta) error {
+	expected := make(map[string]*Bank, len(delta.Added)+len(delta.Changed)+len(delta.Removed))
+	for _, bank := range delta.Added {
+		expected[bank.Swift] = nil
+	}
+	for i := range delta.Changed {
+		expected[delta.Changed[i].Swift] = &delta.Changed[i].Before
+	}
+	for i := range delta.Removed {
+		expected[delta.Removed[i].Swift] = &delta.Removed[i]
+	}
+	if len(expected) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(expected))
+	for swift := range expected {
+		keys = append(keys, ks.bank(swift))
+	}
+	if err := tx.Watch(ctx, keys...).Err(); err != nil {
+		return fmt.Errorf("failed to watch banks: %w", err)
+	}
+
+	cmds := make(map[string]*redis.MapStringStringCmd, len(expected))
+	_, err := tx.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for swift := range expected {
+			cmds[swift] = pipe.HGetAll(ctx, ks.bank(swift))
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get bank data: %w", err)
+	}
+
+	for swift, want := range expected {
+		cmd := cmds[swift]
+		if len(cmd.Val()) == 0 {
+			if want != nil {
+				return fmt.Errorf("%s was deleted: %w", swift, ErrDeltaOutdated)
+			}
+			continue
+		}
+		if want == nil {
+			return fmt.Errorf("%s was created: %w", swift, ErrDeltaOutdated)
+		}
+		var stored Bank
+		if err := cmd.Scan(&stored); err != nil {
+			return fmt.Errorf("failed to parse bank %s: %w", swift, err)
+		}
+		if stored.Revision != want.Revision || len(diffBankFields(stored, *want)) > 0 {
+			return fmt.Errorf("%s was changed: %w", swift, ErrDeltaOutdated)
+		}
+	}
+	return nil
+}
+
+func (s *RedisStore) allBanks(ctx context.Context, ks keyspace) (map[string]Bank, error) {
+	var keys []string
+	iter := s.client.Scan(ctx, 0, ks.bank("*"), 1000).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan bank keys: %w", err)
+	}
+
+	pipe := s.client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = pipe.HGetAll(ctx, key)
+	}
+	if len(keys) > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, fmt.Errorf("failed to get bank data: %w", err)
+		}
+	}
+
+	banks := make(map[string]Bank, len(keys))
+	for _, cmd := range cmds {
+		var bank Bank
+		if err := cmd.Scan(&bank); err != nil {
+			return nil, fmt.Errorf("failed to parse bank data: %w", err)
+		}
+		if bank.Swift != "" {
+			banks[bank.Swift] = bank
+		}
+	}
+
+	return banks, nil
+}
+
+func diffBankFields(before, after Bank) []FieldChange {
+	fields := []struct {
+		name          string
+		before, after string
+	}{
+		{"countryISO2", before.ISO2, after.ISO2},
+		{"bankName", before.Name, after.Name},
+		{"type", before.Type, after.Type},
+		{"address", before.Address, after.Address},
+		{"town", before.Town, after.Town},
+		{"countryName", before.Country, after.Country},
+		{"timezone", before.Timezone, after.Timezone},
+	}
+
+	var changes []FieldChange
+	for _, field := range fields {
+		if field.before != field.after {
+			changes = append(changes, FieldChange{Field: field.name, Before: field.before, After: field.after})
+		}
+	}
+	return changes
+}
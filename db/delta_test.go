@@ -0,0 +1,127 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/grysj/remitly-api/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffAndApplyDelta(t *testing.T) {
+	require.NoError(t, testStore.client.FlushDB(testCtx).Err())
+
+	initial := []parser.CsvRow{
+		{ISO2: "AL", Swift: "AAISALTRXXX", Type: "BIC11", Name: "UNITED BANK OF ALBANIA SH.A", Town: "TIRANA", Country: "ALBANIA", Timezone: "Europe/Tirane"},
+		{ISO2: "BG", Swift: "ABIEBGS1XXX", Type: "BIC11", Name: "ABV INVESTMENTS LTD", Town: "VARNA", Country: "BULGARIA", Timezone: "Europe/Sofia"},
+		{ISO2: "BG", Swift: "ABIEBGS1001", Type: "BIC11", Name: "ABV INVESTMENTS LTD", Town: "SOFIA", Country: "BULGARIA", Timezone: "Europe/Sofia"},
+		{ISO2: "MC", Swift: "BAERMCMCXXX", Type: "BIC11", Name: "BANK JULIUS BAER (MONACO) S.A.M.", Town: "MONACO", Country: "MONACO", Timezone: "Europe/Monaco"},
+	}
+	require.NoError(t, testStore.AddBanksFromCSV(initial))
+
+	incoming := []parser.CsvRow{
+		{ISO2: "AL", Swift: "AAISALTRXXX", Type: "BIC11", Name: "UNITED BANK OF ALBANIA SH.A", Town: "TIRANA", Country: "ALBANIA", Timezone: "Europe/Tirane"},
+		{ISO2: "BG", Swift: "ABIEBGS1XXX", Type: "BIC11", Name: "ABV Investments PLC", Town: "VARNA", Country: "BULGARIA", Timezone: "Europe/Sofia"},
+		{ISO2: "UY", Swift: "AFAAUYM1XXX", Type: "BIC11", Name: "AFINIDAD A.F.A.P.S.A.", Town: "MONTEVIDEO", Country: "URUGUAY", Timezone: "America/Montevideo"},
+	}
+
+	delta, err := testStore.DiffBanks(incoming)
+	require.NoError(t, err)
+
+	require.Len(t, delta.Added, 1)
+	assert.Equal(t, "AFAAUYM1XXX", delta.Added[0].Swift)
+
+	require.Len(t, delta.Removed, 2)
+	assert.Equal(t, "ABIEBGS1001", delta.Removed[0].Swift)
+	assert.Equal(t, "BAERMCMCXXX", delta.Removed[1].Swift)
+
+	require.Len(t, delta.Changed, 1)
+	assert.Equal(t, "ABIEBGS1XXX", delta.Changed[0].Swift)
+	assert.Equal(t, []FieldChange{{Field: "bankName", Before: "ABV INVESTMENTS LTD", After: "ABV INVESTMENTS PLC"}}, delta.Changed[0].Changes)
+
+	assert.Equal(t, 1, delta.Unchanged)
+
+	exists, err := testStore.client.Exists(testCtx, "swiftCode:AFAAUYM1XXX").Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), exists, "diff must not write")
+
+	require.NoError(t, testStore.ApplyDelta(delta))
+
+	exists, err = testStore.client.Exists(testCtx, "swiftCode:BAERMCMCXXX", "swiftCode:ABIEBGS1001").Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), exists)
+
+	members, err := testStore.client.SMembers(testCtx, "idx:countryISO2:BG").Result()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"swiftCode:ABIEBGS1XXX"}, members)
+
+	exists, err = testStore.client.Exists(testCtx, "branch:ABIEBGS1", "idx:countryISO2:MC").Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), exists)
+
+	countries, err := testStore.client.HGetAll(testCtx, "countries").Result()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"AL": "ALBANIA", "BG": "BULGARIA", "UY": "URUGUAY"}, countries)
+
+	bank, err := testStore.GetBankFromSwift("ABIEBGS1XXX")
+	require.NoError(t, err)
+	assert.Equal(t, "ABV INVESTMENTS PLC", bank.Name)
+
+	delta, err = testStore.DiffBanks(incoming)
+	require.NoError(t, err)
+	assert.True(t, delta.IsEmpty())
+	assert.Equal(t, 3, delta.Unchanged)
+}
+
+func TestApplyOutdatedDelta(t *testing.T) {
+	initial := []parser.CsvRow{
+		{ISO2: "BG", Swift: "ABIEBGS1XXX", Name: "ABV INVESTMENTS LTD", Town: "VARNA", Country: "BULGARIA"},
+	}
+	incoming := []parser.CsvRow{
+		{ISO2: "BG", Swift: "ABIEBGS1XXX", Name: "ABV INVESTMENTS PLC", Town: "VARNA", Country: "BULGARIA"},
+		{ISO2: "UY", Swift: "AFAAUYM1XXX", Name: "AFINIDAD A.F.A.P.S.A.", Town: "MONTEVIDEO", Country: "URUGUAY"},
+	}
+
+	tests := []struct {
+		name  string
+		write func(t *testing.T)
+	}{
+		{
+			name: "changed_code_updated_through_api",
+			write: func(t *testing.T) {
+				_, err := testStore.UpdateBank(UpdateBankParams{Bank: Bank{ISO2: "BG", Swift: "ABIEBGS1XXX", Name: "ABV", Town: "SOFIA"}})
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "added_code_created_through_api",
+			write: func(t *testing.T) {
+				_, err := testStore.AddBankToDB(Bank{ISO2: "UY", Swift: "AFAAUYM1XXX", Name: "AFINIDAD", Town: "SALTO", Country: "URUGUAY"})
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, testStore.client.FlushDB(testCtx).Err())
+			require.NoError(t, testStore.AddBanksFromCSV(initial))
+
+			delta, err := testStore.DiffBanks(incoming)
+			require.NoError(t, err)
+			tt.write(t)
+
+			assert.ErrorIs(t, testStore.ApplyDelta(delta), ErrDeltaOutdated)
+			report, err := testStore.CheckConsistency(CheckConsistencyParams{})
+			require.NoError(t, err)
+			assert.Empty(t, report.Issues, "an outdated delta writes nothing")
+
+			delta, err = testStore.DiffBanks(incoming)
+			require.NoError(t, err)
+			require.NoError(t, testStore.ApplyDelta(delta))
+			bank, err := testStore.GetBank("ABIEBGS1XXX")
+			require.NoError(t, err)
+			assert.Equal(t, "ABV INVESTMENTS PLC", bank.Name)
+		})
+	}
+}
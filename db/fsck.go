@@ -0,0 +1,271 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/grysj/remitly-api/bic"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	IssueDanglingIndexMember = "dangling_index_member"
+	IssueMissingIndexEntry   = "missing_index_entry"
+	IssueOrphanBranchSet     = "branch_set_without_headquarters"
+	IssueUnusedCountry       = "unused_country"
+)
+
+// ConsistencyIssue points at one secondary index entry that does not agree
+// with the bank hashes. Key is the Redis key of the index.
+type ConsistencyIssue struct {
+	Kind   string `json:"kind"`
+	Key    string `json:"key"`
+	Member string `json:"member,omitempty"`
+}
+
+type ConsistencyReport struct {
+	Dataset  string             `json:"dataset"`
+	Banks    int                `json:"banks"`
+	Issues   []ConsistencyIssue `json:"issues"`
+	Repaired bool               `json:"repaired"`
+}
+
+type CheckConsistencyParams struct {
+	Repair bool
+}
+
+// keyspaceScan is everything the checker reads from one dataset version.
+type keyspaceScan struct {
+	banks     map[string]Bank
+	indexes   map[string][]string
+	countries map[string]string
+}
+
+// CheckConsistency compares the secondary indexes of a dataset version with
+// its bank hashes. With Repair, every index of the active version is dropped
+// and rebuilt from the hashes in one transaction; the report still lists
+// the issues found before the rebuild. A bank written between the scan and
+// the rebuild makes the repair start over, so its index entries are never
+// dropped.
+func (s *RedisStore) CheckConsistency(params CheckConsistencyParams) (*ConsistencyReport, error) {
+	ctx := context.Background()
+
+	if !params.Repair {
+		ks, err := s.active(ctx)
+		if err != nil {
+			return nil, err
+		}
+		scan, err := s.scanKeyspace(ctx, ks)
+		if err != nil {
+			return nil, err
+		}
+		return consistencyReport(ks, scan), nil
+	}
+
+	var report *ConsistencyReport
+	err := s.update(ctx, func(tx *redis.Tx, ks keyspace) error {
+		if err := tx.Watch(ctx, writeSeqKey).Err(); err != nil {
+			return fmt.Errorf("failed to watch writes: %w", err)
+		}
+		scan, err := s.scanKeyspace(ctx, ks)
+		if err != nil {
+			return err
+		}
+		report = consistencyReport(ks, scan)
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			rebuildIndexes(ctx, pipe, ks, scan)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to rebuild indexes: %w", err)
+		}
+		report.Repaired = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *RedisStore) scanKeyspace(ctx context.Context, ks keyspace) (*keyspaceScan, error) {
+	scan := &keyspaceScan{
+		banks:   make(map[string]Bank),
+		indexes: make(map[string][]string),
+	}
+
+	bankKeys, err := s.scanKeys(ctx, ks.bank("*"))
+	if err != nil {
+		return nil, err
+	}
+	pipe := s.client.Pipeline()
+	bankCmds := make([]*redis.MapStringStringCmd, len(bankKeys))
+	for i, key := range bankKeys {
+		bankCmds[i] = pipe.HGetAll(ctx, key)
+	}
+	if len(bankKeys) > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, fmt.Errorf("failed to get bank data: %w", err)
+		}
+	}
+	for i, cmd := range bankCmds {
+		var bank Bank
+		if err := cmd.Scan(&bank); err != nil {
+			return nil, fmt.Errorf("failed to parse bank %s: %w", bankKeys[i], err)
+		}
+		bank.Swift = strings.TrimPrefix(bankKeys[i], ks.bank(""))
+		scan.banks[bank.Swift] = bank
+	}
+
+	for _, index := range []struct {
+		pattern string
+		sorted  bool
+	}{
+		{ks.iso2Index("*"), false},
+		{ks.branches("*"), false},
+		{ks.terms("*"), true},
+		{ks.swiftLex(), true},
+		{ks.nameLex(), true},
+		{ks.townIndex("*"), true},
+	} {
+		if err := s.readIndex(ctx, index.pattern, index.sorted, scan.indexes); err != nil {
+			return nil, err
+		}
+	}
+
+	scan.countries, err = s.client.HGetAll(ctx, ks.countries()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read countries: %w", err)
+	}
+	return scan, nil
+}
+
+// readIndex reads the members of every set, or sorted set, matching pattern.
+func (s *RedisStore) readIndex(ctx context.Context, pattern string, sorted bool, members map[string][]string) error {
+	keys, err := s.scanKeys(ctx, pattern)
+	if err != nil {
+		return err
+	}
+	for start := 0; start < len(keys); start += reloadChunkSize {
+		chunk := keys[start:min(start+reloadChunkSize, len(keys))]
+		pipe := s.client.Pipeline()
+		cmds := make([]*redis.StringSliceCmd, len(chunk))
+		for i, key := range chunk {
+			if sorted {
+				cmds[i] = pipe.ZRange(ctx, key, 0, -1)
+			} else {
+				cmds[i] = pipe.SMembers(ctx, key)
+			}
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("failed to read indexes: %w", err)
+		}
+		for i, key := range chunk {
+			members[key] = cmds[i].Val()
+		}
+	}
+	return nil
+}
+
+func (s *RedisStore) scanKeys(ctx context.Context, pattern string) ([]string, error) {
+	var keys []string
+	iter := s.client.Scan(ctx, 0, pattern, reloadChunkSize).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan keys: %w", err)
+	}
+	return keys, nil
+}
+
+func consistencyReport(ks keyspace, scan *keyspaceScan) *ConsistencyReport {
+	report := &ConsistencyReport{
+		Dataset: ks.version,
+		Banks:   len(scan.banks),
+		Issues:  []ConsistencyIssue{},
+	}
+	add := func(kind, key, member string) {
+		report.Issues = append(report.Issues, ConsistencyIssue{Kind: kind, Key: key, Member: member})
+	}
+
+	expected := make(map[string]bool)
+	for _, bank := range scan.banks {
+		for _, entry := range bankIndexEntries(ks, bank) {
+			expected[entry.key+"\x00"+entry.member] = true
+		}
+	}
+	stored := make(map[string]bool)
+	for key, members := range scan.indexes {
+		for _, member := range members {
+			stored[key+"\x00"+member] = true
+			if !expected[key+"\x00"+member] {
+				add(IssueDanglingIndexMember, key, member)
+			}
+		}
+		if bic8, ok := strings.CutPrefix(key, ks.branches("")); ok {
+			code, err := bic.Parse(bic8)
+			if err != nil {
+				add(IssueOrphanBranchSet, key, "")
+			} else if _, ok := scan.banks[code.Headquarters().BIC11()]; !ok {
+				add(IssueOrphanBranchSet, key, "")
+			}
+		}
+	}
+
+	used := make(map[string]bool)
+	for _, bank := range scan.banks {
+		for _, entry := range bankIndexEntries(ks, bank) {
+			if !stored[entry.key+"\x00"+entry.member] {
+				add(IssueMissingIndexEntry, entry.key, entry.member)
+			}
+		}
+		used[bank.ISO2] = true
+		if _, ok := scan.countries[bank.ISO2]; !ok && bank.Country != "" {
+			add(IssueMissingIndexEntry, ks.countries(), bank.ISO2)
+		}
+	}
+	for iso2 := range scan.countries {
+		if !used[iso2] {
+			add(IssueUnusedCountry, ks.countries(), iso2)
+		}
+	}
+
+	report.Issues = uniqueIssues(report.Issues)
+	return report
+}
+
+func uniqueIssues(issues []ConsistencyIssue) []ConsistencyIssue {
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].Kind != issues[j].Kind {
+			return issues[i].Kind < issues[j].Kind
+		}
+		if issues[i].Key != issues[j].Key {
+			return issues[i].Key < issues[j].Key
+		}
+		return issues[i].Member < issues[j].Member
+	})
+	unique := issues[:0]
+	for i, issue := range issues {
+		if i == 0 || issue != issues[i-1] {
+			unique = append(unique, issue)
+		}
+	}
+	return unique
+}
+
+// rebuildIndexes replaces every secondary index of the version with the
+// entries writeBank would create for the scanned hashes.
+func rebuildIndexes(ctx context.Context, pipe redis.Pipeliner, ks keyspace, scan *keyspaceScan) {
+	for key := range scan.indexes {
+		pipe.Del(ctx, key)
+	}
+	pipe.Del(ctx, ks.countries())
+
+	for _, bank := range scan.banks {
+		writeIndexes(ctx, pipe, ks, bank)
+	}
+}
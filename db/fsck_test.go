@@ -0,0 +1,153 @@
+package db
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/grysj/remitly-api/parser"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckConsistency(t *testing.T) {
+	rows := []parser.CsvRow{
+		{ISO2: "BG", Swift: "ABIEBGS1XXX", Name: "ABV INVESTMENTS LTD", Country: "BULGARIA"},
+		{ISO2: "BG", Swift: "ABIEBGS1001", Name: "ABV INVESTMENTS LTD", Country: "BULGARIA"},
+		{ISO2: "AL", Swift: "AAISALTRXXX", Name: "UNITED BANK OF ALBANIA SH.A", Country: "ALBANIA"},
+		{ISO2: "PL", Swift: "ALBPPLP1BMW", Name: "ALIOR BANK S.A.", Country: "POLAND"},
+	}
+
+	tests := []struct {
+		name  string
+		drift func(t *testing.T)
+		want  []ConsistencyIssue
+	}{
+		{
+			name:  "Consistent Store",
+			drift: func(t *testing.T) {},
+			want:  []ConsistencyIssue{},
+		},
+		{
+			name: "Dangling And Missing Entries",
+			drift: func(t *testing.T) {
+				require.NoError(t, testStore.client.Del(testCtx, "ds:1:swiftCode:ABIEBGS1001").Err())
+				require.NoError(t, testStore.client.SRem(testCtx, "ds:1:idx:countryISO2:AL", "ds:1:swiftCode:AAISALTRXXX").Err())
+				require.NoError(t, testStore.client.SAdd(testCtx, "ds:1:idx:countryISO2:BG", "ds:1:swiftCode:AAISALTRXXX").Err())
+			},
+			want: []ConsistencyIssue{
+				{Kind: IssueDanglingIndexMember, Key: "ds:1:branch:ABIEBGS1", Member: "ABIEBGS1001"},
+				{Kind: IssueDanglingIndexMember, Key: "ds:1:idx:countryISO2:BG", Member: "ds:1:swiftCode:AAISALTRXXX"},
+				{Kind: IssueDanglingIndexMember, Key: "ds:1:idx:countryISO2:BG", Member: "ds:1:swiftCode:ABIEBGS1001"},
+				{Kind: IssueDanglingIndexMember, Key: "ds:1:idx:lex:name", Member: "ABV INVESTMENTS LTD\x00ABIEBGS1001"},
+				{Kind: IssueDanglingIndexMember, Key: "ds:1:idx:lex:swift", Member: "ABIEBGS1001"},
+				{Kind: IssueDanglingIndexMember, Key: "ds:1:idx:term:ABV", Member: "ds:1:swiftCode:ABIEBGS1001"},
+				{Kind: IssueDanglingIndexMember, Key: "ds:1:idx:term:INVESTMENTS", Member: "ds:1:swiftCode:ABIEBGS1001"},
+				{Kind: IssueDanglingIndexMember, Key: "ds:1:idx:term:LTD", Member: "ds:1:swiftCode:ABIEBGS1001"},
+				{Kind: IssueMissingIndexEntry, Key: "ds:1:idx:countryISO2:AL", Member: "ds:1:swiftCode:AAISALTRXXX"},
+			},
+		},
+		{
+			name: "Branch Set Without Headquarters",
+			drift: func(t *testing.T) {
+				require.NoError(t, testStore.client.Del(testCtx, "ds:1:swiftCode:ALBPPLP1XXX").Err())
+			},
+			want: []ConsistencyIssue{
+				{Kind: IssueOrphanBranchSet, Key: "ds:1:branch:ALBPPLP1"},
+				{Kind: IssueDanglingIndexMember, Key: "ds:1:idx:countryISO2:PL", Member: "ds:1:swiftCode:ALBPPLP1XXX"},
+				{Kind: IssueDanglingIndexMember, Key: "ds:1:idx:lex:name", Member: "ALIOR BANK S.A.\x00ALBPPLP1XXX"},
+				{Kind: IssueDanglingIndexMember, Key: "ds:1:idx:lex:swift", Member: "ALBPPLP1XXX"},
+				{Kind: IssueDanglingIndexMember, Key: "ds:1:idx:term:ALIOR", Member: "ds:1:swiftCode:ALBPPLP1XXX"},
+				{Kind: IssueDanglingIndexMember, Key: "ds:1:idx:term:BANK", Member: "ds:1:swiftCode:ALBPPLP1XXX"},
+			},
+		},
+		{
+			name: "Search Terms Out Of Date",
+			drift: func(t *testing.T) {
+				require.NoError(t, testStore.client.ZRem(testCtx, "ds:1:idx:term:ALBANIA", "ds:1:swiftCode:AAISALTRXXX").Err())
+				require.NoError(t, testStore.client.ZAdd(testCtx, "ds:1:idx:term:MALTA", redis.Z{Score: 1, Member: "ds:1:swiftCode:AAISALTRXXX"}).Err())
+			},
+			want: []ConsistencyIssue{
+				{Kind: IssueDanglingIndexMember, Key: "ds:1:idx:term:MALTA", Member: "ds:1:swiftCode:AAISALTRXXX"},
+				{Kind: IssueMissingIndexEntry, Key: "ds:1:idx:term:ALBANIA", Member: "ds:1:swiftCode:AAISALTRXXX"},
+			},
+		},
+		{
+			name: "Countries Out Of Date",
+			drift: func(t *testing.T) {
+				require.NoError(t, testStore.client.HSet(testCtx, "ds:1:countries", "MT", "MALTA").Err())
+				require.NoError(t, testStore.client.HDel(testCtx, "ds:1:countries", "PL").Err())
+			},
+			want: []ConsistencyIssue{
+				{Kind: IssueMissingIndexEntry, Key: "ds:1:countries", Member: "PL"},
+				{Kind: IssueUnusedCountry, Key: "ds:1:countries", Member: "MT"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, testStore.client.FlushDB(testCtx).Err())
+			_, err := testStore.ReloadDataset(ReloadDatasetParams{Rows: rows})
+			require.NoError(t, err)
+			// a branch whose headquarters is stored until a test removes it
+			_, err = testStore.AddBankToDB(Bank{ISO2: "PL", Swift: "ALBPPLP1XXX", Name: "ALIOR BANK S.A.", Country: "POLAND"})
+			require.NoError(t, err)
+			tt.drift(t)
+
+			report, err := testStore.CheckConsistency(CheckConsistencyParams{})
+			require.NoError(t, err)
+			assert.Equal(t, "1", report.Dataset)
+			assert.Equal(t, tt.want, report.Issues)
+			assert.False(t, report.Repaired)
+
+			repaired, err := testStore.CheckConsistency(CheckConsistencyParams{Repair: true})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, repaired.Issues)
+			assert.True(t, repaired.Repaired)
+
+			after, err := testStore.CheckConsistency(CheckConsistencyParams{})
+			require.NoError(t, err)
+			for _, issue := range after.Issues {
+				assert.Equal(t, IssueOrphanBranchSet, issue.Kind, "only data issues remain after a repair")
+			}
+
+			banks, err := testStore.GetBanksByISO2("AL")
+			require.NoError(t, err)
+			assert.Len(t, banks, 1)
+		})
+	}
+	require.NoError(t, testStore.client.FlushDB(testCtx).Err())
+}
+
+func TestRepairDuringWrites(t *testing.T) {
+	require.NoError(t, testStore.client.FlushDB(testCtx).Err())
+	_, err := testStore.ReloadDataset(ReloadDatasetParams{Rows: []parser.CsvRow{
+		{ISO2: "AL", Swift: "AAISALTRXXX", Name: "UNITED BANK OF ALBANIA SH.A", Country: "ALBANIA"},
+	}})
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 40; i++ {
+			_, err := testStore.AddBankToDB(Bank{ISO2: "PL", Swift: fmt.Sprintf("ALBPPLP1%03d", i), Name: "ALIOR BANK", Town: "KRAKOW", Country: "POLAND"})
+			assert.NoError(t, err)
+		}
+	}()
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		default:
+			// a repair that keeps losing to the writes gives up, which is fine
+			testStore.CheckConsistency(CheckConsistencyParams{Repair: true})
+		}
+	}
+
+	report, err := testStore.CheckConsistency(CheckConsistencyParams{})
+	require.NoError(t, err)
+	for _, issue := range report.Issues {
+		assert.NotEqual(t, IssueMissingIndexEntry, issue.Kind, "a repair dropped %s from %s", issue.Member, issue.Key)
+	}
+}
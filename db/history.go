@@ -0,0 +1,213 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// History streams live outside the dataset versions, so the log of a code
+// survives reloads, rollbacks and the deletion of the code itself.
+const historyKeyPrefix = "history:"
+
+const (
+	ChangeCreate  = "create"
+	ChangeUpdate  = "update"
+	ChangeDelete  = "delete"
+	ChangeRestore = "restore"
+)
+
+const (
+	SourceAPI      = "api"
+	SourceImport   = "import"
+	SourceRollback = "rollback"
+)
+
+// DefaultActor is recorded as the author of changes made by a store that
+// was not given an actor, such as imports and writes without X-Actor.
+const DefaultActor = "system"
+
+type HistoryEvent struct {
+	ID        string        `json:"id"`
+	Timestamp string        `json:"timestamp"`
+	Action    string        `json:"action"`
+	Actor     string        `json:"actor"`
+	Source    string        `json:"source"`
+	Dataset   string        `json:"dataset,omitempty"`
+	Changes   []FieldChange `json:"changes,omitempty"`
+	Old       *Bank         `json:"old,omitempty"`
+	New       *Bank         `json:"new,omitempty"`
+}
+
+func historyKey(swift string) string {
+	return historyKeyPrefix + swift
+}
+
+// WithActor returns a store that records actor as the author of the
+// changes it makes.
+func (s *RedisStore) WithActor(actor string) *Store {
+	acting := *s
+	acting.actor = actor
+	return &Store{DBQuerier: &acting}
+}
+
+func (s *RedisStore) actorName() string {
+	if s.actor == "" {
+		return DefaultActor
+	}
+	return s.actor
+}
+
+// recordChange appends a change of one code to its history stream. Either
+// side may be nil for a create or a delete; updates that leave every field
+// as it was are not recorded.
+func (s *RedisStore) recordChange(ctx context.Context, pipe redis.Pipeliner, ks keyspace, source string, old, new *Bank) {
+	var action, swift string
+	switch {
+	case old == nil && new == nil:
+		return
+	case old == nil:
+		action, swift = ChangeCreate, new.Swift
+	case new == nil:
+		action, swift = ChangeDelete, old.Swift
+	default:
+		if len(diffBankFields(*old, *new)) == 0 {
+			return
+		}
+		action, swift = ChangeUpdate, new.Swift
+	}
+	s.recordEvent(ctx, pipe, ks, swift, action, source, old, new)
+}
+
+func (s *RedisStore) recordEvent(ctx context.Context, pipe redis.Pipeliner, ks keyspace, swift, action, source string, old, new *Bank) {
+	values := map[string]interface{}{
+		"action":    action,
+		"actor":     s.actorName(),
+		"source":    source,
+		"dataset":   ks.version,
+		"timestamp": time.Now().UTC().Format(time.RFC3339Nano),
+	}
+	if old != nil {
+		encoded, _ := json.Marshal(old)
+		values["old"] = string(encoded)
+	}
+	if new != nil {
+		encoded, _ := json.Marshal(new)
+		values["new"] = string(encoded)
+	}
+
+	pipe.XAdd(ctx, &redis.XAddArgs{
+		Stream: historyKey(swift),
+		Values: values,
+	})
+}
+
+// recordDatasetChanges logs the differences between two dataset versions,
+// in chunks like the reload itself.
+func (s *RedisStore) recordDatasetChanges(ctx context.Context, from, to keyspace, source string) error {
+	before, err := s.allBanks(ctx, from)
+	if err != nil {
+		return err
+	}
+	after, err := s.allBanks(ctx, to)
+	if err != nil {
+		return err
+	}
+
+	swifts := make([]string, 0, len(before)+len(after))
+	for swift := range after {
+		swifts = append(swifts, swift)
+	}
+	for swift := range before {
+		if _, ok := after[swift]; !ok {
+			swifts = append(swifts, swift)
+		}
+	}
+	sort.Strings(swifts)
+
+	for start := 0; start < len(swifts); start += reloadChunkSize {
+		pipe := s.client.Pipeline()
+		for _, swift := range swifts[start:min(start+reloadChunkSize, len(swifts))] {
+			s.recordChange(ctx, pipe, to, source, bankOrNil(before, swift), bankOrNil(after, swift))
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("failed to record dataset changes: %w", err)
+		}
+	}
+	return nil
+}
+
+func bankOrNil(banks map[string]Bank, swift string) *Bank {
+	bank, ok := banks[swift]
+	if !ok {
+		return nil
+	}
+	return &bank
+}
+
+// storedBank reads a bank inside a transaction, returning nil when the code
+// is not stored.
+func storedBank(ctx context.Context, tx *redis.Tx, ks keyspace, swift string) (*Bank, error) {
+	cmd := tx.HGetAll(ctx, ks.bank(swift))
+	if err := cmd.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get bank data: %w", err)
+	}
+	if len(cmd.Val()) == 0 {
+		return nil, nil
+	}
+	var bank Bank
+	if err := cmd.Scan(&bank); err != nil {
+		return nil, fmt.Errorf("failed to parse bank data: %w", err)
+	}
+	bank.Swift = swift
+	return &bank, nil
+}
+
+// GetBankHistory returns the change log of a code, oldest first. It is empty
+// for codes that were never changed through the store.
+func (s *RedisStore) GetBankHistory(swift string) ([]HistoryEvent, error) {
+	ctx := context.Background()
+
+	messages, err := s.client.XRange(ctx, historyKey(strings.ToUpper(swift)), "-", "+").Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history of %s: %w", swift, err)
+	}
+
+	events := make([]HistoryEvent, 0, len(messages))
+	for _, message := range messages {
+		event := HistoryEvent{
+			ID:        message.ID,
+			Timestamp: streamValue(message, "timestamp"),
+			Action:    streamValue(message, "action"),
+			Actor:     streamValue(message, "actor"),
+			Source:    streamValue(message, "source"),
+			Dataset:   streamValue(message, "dataset"),
+		}
+		for field, target := range map[string]**Bank{"old": &event.Old, "new": &event.New} {
+			encoded := streamValue(message, field)
+			if encoded == "" {
+				continue
+			}
+			var bank Bank
+			if err := json.Unmarshal([]byte(encoded), &bank); err != nil {
+				return nil, fmt.Errorf("failed to parse history event %s: %w", message.ID, err)
+			}
+			*target = &bank
+		}
+		if event.Old != nil && event.New != nil {
+			event.Changes = diffBankFields(*event.Old, *event.New)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func streamValue(message redis.XMessage, field string) string {
+	value, _ := message.Values[field].(string)
+	return value
+}
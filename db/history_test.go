@@ -0,0 +1,98 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/grysj/remitly-api/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBankHistory(t *testing.T) {
+	require.NoError(t, testStore.client.FlushDB(testCtx).Err())
+
+	_, err := testStore.ReloadDataset(ReloadDatasetParams{
+		Rows: []parser.CsvRow{
+			{ISO2: "AL", Swift: "AAISALTRXXX", Name: "UNITED BANK OF ALBANIA SH.A", Country: "ALBANIA"},
+		},
+		Source: "SWIFT_CODES.csv",
+	})
+	require.NoError(t, err)
+
+	clerk := testStore.WithActor("clerk@example.com")
+	update := UpdateBankParams{Bank: Bank{ISO2: "AL", Swift: "AAISALTRXXX", Name: "United Bank of Albania", Country: "ALBANIA", Address: "TIRANA"}}
+	_, err = clerk.UpdateBank(update)
+	require.NoError(t, err)
+	// writing the same values again is not a change
+	_, err = clerk.UpdateBank(update)
+	require.NoError(t, err)
+	require.NoError(t, clerk.DeleteBankFromDB(DeleteBankParams{Swift: "AAISALTRXXX"}))
+	// deleting a missing code is not a change either
+	require.NoError(t, clerk.DeleteBankFromDB(DeleteBankParams{Swift: "AAISALTRXXX"}))
+
+	events, err := testStore.GetBankHistory("aaisaltrxxx")
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+
+	assert.Equal(t, ChangeCreate, events[0].Action)
+	assert.Equal(t, DefaultActor, events[0].Actor)
+	assert.Equal(t, SourceImport, events[0].Source)
+	assert.Equal(t, "1", events[0].Dataset)
+	assert.Nil(t, events[0].Old)
+	require.NotNil(t, events[0].New)
+	assert.Equal(t, "UNITED BANK OF ALBANIA SH.A", events[0].New.Name)
+
+	assert.Equal(t, ChangeUpdate, events[1].Action)
+	assert.Equal(t, "clerk@example.com", events[1].Actor)
+	assert.Equal(t, SourceAPI, events[1].Source)
+	assert.Equal(t, []FieldChange{
+		{Field: "bankName", Before: "UNITED BANK OF ALBANIA SH.A", After: "UNITED BANK OF ALBANIA"},
+		{Field: "address", Before: "", After: "TIRANA"},
+	}, events[1].Changes)
+	assert.NotEmpty(t, events[1].Timestamp)
+
+	assert.Equal(t, ChangeDelete, events[2].Action)
+	require.NotNil(t, events[2].Old)
+	assert.Equal(t, "UNITED BANK OF ALBANIA", events[2].Old.Name)
+	assert.Nil(t, events[2].New)
+
+	events, err = testStore.GetBankHistory("ABIEBGS1XXX")
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func TestDatasetHistory(t *testing.T) {
+	require.NoError(t, testStore.client.FlushDB(testCtx).Err())
+
+	first := []parser.CsvRow{
+		{ISO2: "AL", Swift: "AAISALTRXXX", Name: "UNITED BANK OF ALBANIA SH.A", Country: "ALBANIA"},
+		{ISO2: "BG", Swift: "ABIEBGS1XXX", Name: "ABV INVESTMENTS LTD", Country: "BULGARIA"},
+	}
+	second := []parser.CsvRow{
+		{ISO2: "AL", Swift: "AAISALTRXXX", Name: "UNITED BANK OF ALBANIA", Country: "ALBANIA"},
+	}
+	for _, rows := range [][]parser.CsvRow{first, second, second} {
+		_, err := testStore.ReloadDataset(ReloadDatasetParams{Rows: rows})
+		require.NoError(t, err)
+	}
+	_, err := testStore.WithActor("ops").ActivateDataset("1")
+	require.NoError(t, err)
+
+	actions := func(swift string) []string {
+		events, err := testStore.GetBankHistory(swift)
+		require.NoError(t, err)
+		result := make([]string, 0, len(events))
+		for _, event := range events {
+			result = append(result, event.Source+":"+event.Action+":"+event.Dataset)
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"import:create:1", "import:update:2", "rollback:update:1"}, actions("AAISALTRXXX"))
+	assert.Equal(t, []string{"import:create:1", "import:delete:2", "rollback:create:1"}, actions("ABIEBGS1XXX"))
+
+	delta, err := testStore.DiffBanks(second)
+	require.NoError(t, err)
+	require.NoError(t, testStore.ApplyDelta(delta))
+	assert.Equal(t, []string{"import:create:1", "import:delete:2", "rollback:create:1", "import:delete:1"}, actions("ABIEBGS1XXX"))
+}
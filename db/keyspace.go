@@ -0,0 +1,177 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	activeVersionKey = "dataset:active"
+	versionSeqKey    = "dataset:seq"
+	versionKeyPrefix = "ds:"
+)
+
+// a full reload is written in chunks so a large file does not build one
+// huge pipeline in memory
+const reloadChunkSize = 1000
+
+// maxUpdateAttempts bounds the retries of a write that raced with a
+// dataset switch.
+const maxUpdateAttempts = 3
+
+// keyspace builds the keys of one dataset version. The zero value is the
+// unversioned layout used before datasets were versioned, which stays
+// readable until the first reload replaces it.
+type keyspace struct {
+	version string
+}
+
+func (k keyspace) prefix() string {
+	if k.version == "" {
+		return ""
+	}
+	return versionKeyPrefix + k.version + ":"
+}
+
+func (k keyspace) bank(swift string) string {
+	return k.prefix() + bankKeyPrefix + swift
+}
+
+func (k keyspace) iso2Index(iso2 string) string {
+	return k.prefix() + iso2IndexKey + ":" + iso2
+}
+
+func (k keyspace) townIndex(iso2 string) string {
+	return k.prefix() + townIndexKey + ":" + iso2
+}
+
+func (k keyspace) branches(bic8 string) string {
+	return k.prefix() + branchKeyPrefix + bic8
+}
+
+func (k keyspace) terms(term string) string {
+	return k.prefix() + termIndexPrefix + term
+}
+
+func (k keyspace) swiftLex() string {
+	return k.prefix() + swiftLexKey
+}
+
+func (k keyspace) nameLex() string {
+	return k.prefix() + nameLexKey
+}
+
+func (k keyspace) countries() string {
+	return k.prefix() + countriesKey
+}
+
+func (k keyspace) deleted(swift string) string {
+	return k.prefix() + deletedKeyPrefix + swift
+}
+
+func (k keyspace) deletedIndex() string {
+	return k.prefix() + deletedIndexKey
+}
+
+func (k keyspace) cascade(swift string) string {
+	return k.prefix() + cascadeKeyPrefix + swift
+}
+
+// patterns match every key of the version.
+func (k keyspace) patterns() []string {
+	if k.version == "" {
+		return []string{k.bank("*"), k.iso2Index("*"), k.branches("*"), k.countries(),
+			k.deleted("*"), k.deletedIndex(), k.cascade("*"), k.terms("*"),
+			k.swiftLex(), k.nameLex(), k.townIndex("*")}
+	}
+	return []string{k.prefix() + "*"}
+}
+
+func activeKeyspace(ctx context.Context, client redis.Cmdable) (keyspace, error) {
+	version, err := client.Get(ctx, activeVersionKey).Result()
+	if err == redis.Nil {
+		return keyspace{}, nil
+	}
+	if err != nil {
+		return keyspace{}, fmt.Errorf("failed to read active dataset version: %w", err)
+	}
+	return keyspace{version: version}, nil
+}
+
+// active returns the keyspace reads are served from: the pinned version
+// when the store was pinned with AtDataset, the active one otherwise.
+func (s *RedisStore) active(ctx context.Context) (keyspace, error) {
+	if s.pinned != "" {
+		return keyspace{version: s.pinned}, nil
+	}
+	return activeKeyspace(ctx, s.client)
+}
+
+// update runs a write against the active dataset version. The version
+// pointer is watched, so a write that races with a reload is retried
+// against the new version instead of landing in the one being dropped.
+func (s *RedisStore) update(ctx context.Context, fn func(tx *redis.Tx, ks keyspace) error) error {
+	var err error
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		err = s.client.Watch(ctx, func(tx *redis.Tx) error {
+			ks, err := activeKeyspace(ctx, tx)
+			if err != nil {
+				return err
+			}
+			return fn(tx, ks)
+		}, activeVersionKey)
+		if !errors.Is(err, redis.TxFailedErr) {
+			return err
+		}
+	}
+	return fmt.Errorf("dataset changed during update: %w", err)
+}
+
+// writeDataset writes formatted banks into a dataset version. Every bank
+// must have a distinct code: writing one code twice would leave the index
+// entries of the first record behind.
+func (s *RedisStore) writeDataset(ctx context.Context, ks keyspace, banks []Bank) error {
+	revision, err := s.nextRevisions(ctx, len(banks))
+	if err != nil {
+		return err
+	}
+	for start := 0; start < len(banks); start += reloadChunkSize {
+		pipe := s.client.Pipeline()
+		for i, bank := range banks[start:min(start+reloadChunkSize, len(banks))] {
+			bank.Revision = revision + int64(start+i)
+			writeBank(ctx, pipe, ks, bank)
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("failed to write dataset version %s: %w", ks.version, err)
+		}
+	}
+	return nil
+}
+
+func (s *RedisStore) dropKeyspace(ctx context.Context, ks keyspace) error {
+	for _, pattern := range ks.patterns() {
+		iter := s.client.Scan(ctx, 0, pattern, reloadChunkSize).Iterator()
+		var keys []string
+		for iter.Next(ctx) {
+			keys = append(keys, iter.Val())
+			if len(keys) == reloadChunkSize {
+				if err := s.client.Unlink(ctx, keys...).Err(); err != nil {
+					return fmt.Errorf("failed to remove keys: %w", err)
+				}
+				keys = keys[:0]
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return fmt.Errorf("failed to scan keys: %w", err)
+		}
+		if len(keys) > 0 {
+			if err := s.client.Unlink(ctx, keys...).Err(); err != nil {
+				return fmt.Errorf("failed to remove keys: %w", err)
+			}
+		}
+	}
+	return nil
+}
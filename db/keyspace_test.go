@@ -0,0 +1,108 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/grysj/remitly-api/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReloadDataset(t *testing.T) {
+	require.NoError(t, testStore.client.FlushDB(testCtx).Err())
+
+	// data written before datasets were versioned
+	_, err := testStore.AddBankToDB(Bank{ISO2: "MC", Swift: "BAERMCMCXXX", Name: "BANK JULIUS BAER (MONACO) S.A.M.", Country: "MONACO"})
+	require.NoError(t, err)
+
+	first := []parser.CsvRow{
+		{ISO2: "AL", Swift: "AAISALTRXXX", Name: "UNITED BANK OF ALBANIA SH.A", Country: "ALBANIA"},
+		{ISO2: "BG", Swift: "ABIEBGS1XXX", Name: "ABV INVESTMENTS LTD", Country: "BULGARIA"},
+		{ISO2: "BG", Swift: "ABIEBGS1001", Name: "ABV INVESTMENTS LTD", Country: "BULGARIA"},
+	}
+	dataset, err := testStore.ReloadDataset(ReloadDatasetParams{Rows: first, Source: "first.csv"})
+	require.NoError(t, err)
+	assert.Equal(t, "1", dataset.ID)
+	assert.Equal(t, 3, dataset.Rows)
+
+	active, err := testStore.client.Get(testCtx, "dataset:active").Result()
+	require.NoError(t, err)
+	assert.Equal(t, "1", active)
+
+	legacy, err := testStore.client.Keys(testCtx, "swiftCode:*").Result()
+	require.NoError(t, err)
+	assert.Empty(t, legacy, "unversioned keys are dropped after the first reload")
+	for _, key := range []string{"idx:countryISO2:MC", "countries"} {
+		exists, err := testStore.client.Exists(testCtx, key).Result()
+		require.NoError(t, err)
+		assert.Zero(t, exists, key)
+	}
+
+	bank, err := testStore.GetBankFromSwift("BAERMCMCXXX")
+	require.NoError(t, err)
+	assert.Nil(t, bank)
+
+	branches, err := testStore.GetBankBranches("ABIEBGS1XXX")
+	require.NoError(t, err)
+	require.Len(t, branches, 1)
+	assert.Equal(t, "ABIEBGS1001", branches[0].Swift)
+
+	members, err := testStore.client.SMembers(testCtx, "ds:1:idx:countryISO2:BG").Result()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"ds:1:swiftCode:ABIEBGS1XXX", "ds:1:swiftCode:ABIEBGS1001"}, members)
+
+	// writes go to the active version
+	_, err = testStore.AddBankToDB(Bank{ISO2: "UY", Swift: "AFAAUYM1XXX", Name: "AFINIDAD A.F.A.P.S.A.", Country: "URUGUAY"})
+	require.NoError(t, err)
+	exists, err := testStore.client.Exists(testCtx, "ds:1:swiftCode:AFAAUYM1XXX").Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), exists)
+
+	second := []parser.CsvRow{
+		{ISO2: "AL", Swift: "AAISALTRXXX", Name: "UNITED BANK OF ALBANIA", Country: "ALBANIA"},
+	}
+	dataset, err = testStore.ReloadDataset(ReloadDatasetParams{Rows: second, Source: "second.csv"})
+	require.NoError(t, err)
+	assert.Equal(t, "2", dataset.ID)
+
+	previous, err := testStore.client.Keys(testCtx, "ds:1:*").Result()
+	require.NoError(t, err)
+	assert.NotEmpty(t, previous, "the previous version is kept for rollback")
+
+	bank, err = testStore.GetBankFromSwift("AAISALTRXXX")
+	require.NoError(t, err)
+	require.NotNil(t, bank)
+	assert.Equal(t, "UNITED BANK OF ALBANIA", bank.Name)
+
+	banks, err := testStore.GetBanksByISO2("BG")
+	require.NoError(t, err)
+	assert.Empty(t, banks)
+
+	name, err := testStore.client.HGet(testCtx, "ds:2:countries", "AL").Result()
+	require.NoError(t, err)
+	assert.Equal(t, "ALBANIA", name)
+
+	delta, err := testStore.DiffBanks(second)
+	require.NoError(t, err)
+	assert.True(t, delta.IsEmpty())
+}
+
+func TestReloadDatasetDuplicateCodes(t *testing.T) {
+	require.NoError(t, testStore.client.FlushDB(testCtx).Err())
+
+	dataset, err := testStore.ReloadDataset(ReloadDatasetParams{Rows: []parser.CsvRow{
+		{ISO2: "PL", Swift: "ALBPPLPW", Name: "ALIOR BANK", Town: "WARSZAWA", Country: "POLAND"},
+		{ISO2: "PL", Swift: "ALBPPLPWXXX", Name: "ALIOR BANK SPOLKA AKCYJNA", Town: "KRAKOW", Country: "POLAND"},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, dataset.Rows)
+
+	bank, err := testStore.GetBank("ALBPPLPWXXX")
+	require.NoError(t, err)
+	require.NotNil(t, bank)
+	assert.Equal(t, "ALIOR BANK SPOLKA AKCYJNA", bank.Name)
+
+	report, err := testStore.CheckConsistency(CheckConsistencyParams{})
+	require.NoError(t, err)
+	assert.Empty(t, report.Issues, "the index entries of the overwritten row are not written")
+}
@@ -17,14 +17,35 @@ type Bank struct {
 	Name       string `json:"bankName" redis:"bankName"`
 	Type       string `json:"type,omitempty" redis:"type"`
 	Address    string `json:"address,omitempty" redis:"address"`
+	Street     string `json:"street,omitempty" redis:"street"`
+	Postcode   string `json:"postcode,omitempty" redis:"postcode"`
+	Region     string `json:"region,omitempty" redis:"region"`
 	Town       string `json:"town,omitempty" redis:"town"`
 	Country    string `json:"countryName,omitempty" redis:"countryName"`
 	Timezone   string `json:"timezone,omitempty" redis:"timezone"`
 	Headquater bool   `json:"isHeadquater" redis:"isHeadquater"`
+	Revision   int64  `json:"revision,omitempty" redis:"revision"`
 }
 
+// DeleteBankParams selects the code to delete. A non-zero Revision makes the
+// delete conditional on the stored revision.
+// DeleteBankParams names the code to delete. A non-zero Revision makes the
+// delete conditional on the stored revision, and MustExist makes it fail
+// when the code is not stored.
 type DeleteBankParams struct {
-	Swift string `json:"swiftCode" redis:"swiftCode"`
+	Swift     string `json:"swiftCode" redis:"swiftCode"`
+	Revision  int64  `json:"-" redis:"-"`
+	MustExist bool   `json:"-" redis:"-"`
+	Cascade   bool   `json:"-" redis:"-"`
+}
+
+// UpdateBankParams carries the replacement record. A non-zero Revision makes
+// the update conditional on the stored revision, and MustExist reports a
+// missing record as ErrRevisionMismatch instead of ErrBankNotFound.
+type UpdateBankParams struct {
+	Bank      Bank
+	Revision  int64
+	MustExist bool
 }
 
 type GetBankByIsoResult struct {
@@ -57,8 +78,12 @@ type GetBankBySwiftResult struct {
 	Name       string `json:"bankName" redis:"bankName"`
 	Type       string `json:"type,omitempty" redis:"-"`
 	Address    string `json:"address,omitempty" redis:"address"`
-	Town       string `json:"town,omitempty" redis:"-"`
+	Street     string `json:"street,omitempty" redis:"street"`
+	Postcode   string `json:"postcode,omitempty" redis:"postcode"`
+	Region     string `json:"region,omitempty" redis:"region"`
+	Town       string `json:"town,omitempty" redis:"town"`
 	Country    string `json:"countryName,omitempty" redis:"countryName"`
 	Timezone   string `json:"timezone,omitempty" redis:"-"`
 	Headquater bool   `json:"isHeadquater" redis:"isHeadquater"`
+	Revision   int64  `json:"revision,omitempty" redis:"revision"`
 }
@@ -0,0 +1,159 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/grysj/remitly-api/country"
+	"github.com/redis/go-redis/v9"
+)
+
+const termIndexPrefix = "idx:term:"
+
+// searchResultKey holds the intersection of a query's terms. It is created
+// and deleted inside one MULTI, so concurrent searches never see each
+// other's results.
+const searchResultKey = "tmp:search"
+
+// A term found in several fields of a bank scores the sum of their weights.
+const (
+	nameTermWeight    = 3
+	townTermWeight    = 2
+	addressTermWeight = 1
+)
+
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+)
+
+var ErrEmptyQuery = errors.New("search query has no terms")
+
+type SearchBanksParams struct {
+	Query  string
+	ISO2   string
+	Limit  int
+	Offset int
+}
+
+type SearchHit struct {
+	Bank
+	Score float64 `json:"score"`
+}
+
+type SearchResult struct {
+	Total int         `json:"total"`
+	Hits  []SearchHit `json:"hits"`
+}
+
+// searchTerms splits text into upper-case, accent-free words. Apostrophes
+// are dropped so "JULIAN'S" is one word, and single characters are skipped.
+func searchTerms(text string) []string {
+	folded := strings.Map(func(r rune) rune {
+		if r == '\'' || r == '’' {
+			return -1
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return ' '
+	}, country.FoldAccents(text))
+
+	seen := make(map[string]bool)
+	var terms []string
+	for _, term := range strings.Fields(folded) {
+		if len([]rune(term)) < 2 || seen[term] {
+			continue
+		}
+		seen[term] = true
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+// bankTerms returns the search index entries of a bank with their scores.
+func bankTerms(bank Bank) map[string]float64 {
+	terms := make(map[string]float64)
+	for _, field := range []struct {
+		text   string
+		weight float64
+	}{
+		{bank.Name, nameTermWeight},
+		{bank.Town, townTermWeight},
+		{bank.Address, addressTermWeight},
+	} {
+		for _, term := range searchTerms(field.text) {
+			terms[term] += field.weight
+		}
+	}
+	return terms
+}
+
+// SearchBanks returns the banks whose name, town or address contain every
+// word of the query, best matches first. Ties are ordered by SWIFT code.
+func (s *RedisStore) SearchBanks(params SearchBanksParams) (*SearchResult, error) {
+	terms := searchTerms(params.Query)
+	if len(terms) == 0 {
+		return nil, ErrEmptyQuery
+	}
+	limit := params.Limit
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	limit = min(limit, MaxSearchLimit)
+	offset := max(params.Offset, 0)
+
+	ctx := context.Background()
+	ks, err := s.active(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// negative weights rank the best match first in ascending order, which
+	// breaks ties by member
+	store := &redis.ZStore{}
+	for _, term := range terms {
+		store.Keys = append(store.Keys, ks.terms(term))
+		store.Weights = append(store.Weights, -1)
+	}
+	if params.ISO2 != "" {
+		store.Keys = append(store.Keys, ks.iso2Index(strings.ToUpper(params.ISO2)))
+		store.Weights = append(store.Weights, 0)
+	}
+
+	resultKey := ks.prefix() + searchResultKey
+	pipe := s.client.TxPipeline()
+	pipe.ZInterStore(ctx, resultKey, store)
+	total := pipe.ZCard(ctx, resultKey)
+	page := pipe.ZRangeWithScores(ctx, resultKey, int64(offset), int64(offset+limit-1))
+	pipe.Del(ctx, resultKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to search banks: %w", err)
+	}
+
+	matches := page.Val()
+	pipe = s.client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(matches))
+	for i, match := range matches {
+		cmds[i] = pipe.HGetAll(ctx, match.Member.(string))
+	}
+	if len(matches) > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, fmt.Errorf("failed to get bank data: %w", err)
+		}
+	}
+
+	result := &SearchResult{Total: int(total.Val()), Hits: make([]SearchHit, 0, len(matches))}
+	for i, cmd := range cmds {
+		var bank Bank
+		if err := cmd.Scan(&bank); err != nil {
+			return nil, fmt.Errorf("failed to parse bank data: %w", err)
+		}
+		bank.Swift = strings.TrimPrefix(matches[i].Member.(string), ks.bank(""))
+		result.Hits = append(result.Hits, SearchHit{Bank: bank, Score: -matches[i].Score})
+	}
+	return result, nil
+}
@@ -0,0 +1,130 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/grysj/remitly-api/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchTerms(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"PORTOMASO BUSINESS TOWER ST. JULIAN'S", []string{"PORTOMASO", "BUSINESS", "TOWER", "ST", "JULIANS"}},
+		{"Bank Polska Kasa Opieki S.A.", []string{"BANK", "POLSKA", "KASA", "OPIEKI"}},
+		{"Kraków, ul. Wielicka 28", []string{"KRAKOW", "UL", "WIELICKA", "28"}},
+		{"Łódź ŁÓDŹ", []string{"LODZ"}},
+		{"  - ", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			assert.Equal(t, tt.want, searchTerms(tt.text))
+		})
+	}
+}
+
+func TestSearchBanks(t *testing.T) {
+	require.NoError(t, testStore.client.FlushDB(testCtx).Err())
+	_, err := testStore.ReloadDataset(ReloadDatasetParams{Rows: []parser.CsvRow{
+		{ISO2: "PL", Swift: "BPKOPLPWXXX", Name: "PKO BANK POLSKI S.A.", Address: "PULAWSKA 15 WARSZAWA", Town: "WARSZAWA", Country: "POLAND"},
+		{ISO2: "PL", Swift: "PPABPLPKXXX", Name: "BNP PARIBAS BANK POLSKA S.A.", Address: "KASPRZAKA 2 WARSZAWA", Town: "WARSZAWA", Country: "POLAND"},
+		{ISO2: "PL", Swift: "ALBPPLP1BMW", Name: "ALIOR BANK S.A.", Address: "LOPUSZANSKA 38D WARSZAWA", Town: "WARSZAWA", Country: "POLAND"},
+		{ISO2: "PL", Swift: "BREXPLPWMBK", Name: "MBANK S.A. RETAIL BANKING", Address: "KILINSKIEGO 74 LODZ", Town: "ŁÓDŹ", Country: "POLAND"},
+		{ISO2: "MT", Swift: "AKBKMTMTXXX", Name: "AKBANK T.A.S.", Address: "PORTOMASO BUSINESS TOWER", Town: "ST. JULIAN'S", Country: "MALTA"},
+		{ISO2: "MT", Swift: "BPKOMTMTXXX", Name: "WARSZAWA TRADING BANK", Address: "WARSZAWA HOUSE", Town: "VALLETTA", Country: "MALTA"},
+	}})
+	require.NoError(t, err)
+
+	swifts := func(result *SearchResult) []string {
+		codes := make([]string, 0, len(result.Hits))
+		for _, hit := range result.Hits {
+			codes = append(codes, hit.Swift)
+		}
+		return codes
+	}
+
+	tests := []struct {
+		name      string
+		params    SearchBanksParams
+		wantTotal int
+		want      []string
+	}{
+		{
+			name:      "Name Matches Rank Above Town And Address",
+			params:    SearchBanksParams{Query: "warszawa"},
+			wantTotal: 4,
+			want:      []string{"BPKOMTMTXXX", "ALBPPLP1BMW", "BPKOPLPWXXX", "PPABPLPKXXX"},
+		},
+		{
+			name:      "All Words Must Match",
+			params:    SearchBanksParams{Query: "bank polska"},
+			wantTotal: 1,
+			want:      []string{"PPABPLPKXXX"},
+		},
+		{
+			name:      "Country Filter",
+			params:    SearchBanksParams{Query: "warszawa", ISO2: "pl"},
+			wantTotal: 3,
+			want:      []string{"ALBPPLP1BMW", "BPKOPLPWXXX", "PPABPLPKXXX"},
+		},
+		{
+			name:      "Diacritics Are Ignored",
+			params:    SearchBanksParams{Query: "Łódź"},
+			wantTotal: 1,
+			want:      []string{"BREXPLPWMBK"},
+		},
+		{
+			name:      "Paging",
+			params:    SearchBanksParams{Query: "warszawa", Limit: 2, Offset: 2},
+			wantTotal: 4,
+			want:      []string{"BPKOPLPWXXX", "PPABPLPKXXX"},
+		},
+		{
+			name:      "No Match",
+			params:    SearchBanksParams{Query: "bank zurich"},
+			wantTotal: 0,
+			want:      []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := testStore.SearchBanks(tt.params)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTotal, result.Total)
+			assert.Equal(t, tt.want, swifts(result))
+		})
+	}
+
+	t.Run("Empty Query", func(t *testing.T) {
+		_, err := testStore.SearchBanks(SearchBanksParams{Query: "S.A."})
+		assert.ErrorIs(t, err, ErrEmptyQuery)
+	})
+
+	t.Run("Writes Keep The Index Current", func(t *testing.T) {
+		_, err := testStore.UpdateBank(UpdateBankParams{Bank: Bank{Swift: "ALBPPLP1BMW", ISO2: "PL", Name: "ALIOR BANK S.A.", Town: "KRAKOW"}})
+		require.NoError(t, err)
+		result, err := testStore.SearchBanks(SearchBanksParams{Query: "alior warszawa"})
+		require.NoError(t, err)
+		assert.Zero(t, result.Total)
+		result, err = testStore.SearchBanks(SearchBanksParams{Query: "alior kraków"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ALBPPLP1BMW"}, swifts(result))
+
+		require.NoError(t, testStore.DeleteBankFromDB(DeleteBankParams{Swift: "ALBPPLP1BMW"}))
+		result, err = testStore.SearchBanks(SearchBanksParams{Query: "alior"})
+		require.NoError(t, err)
+		assert.Zero(t, result.Total)
+
+		_, err = testStore.RestoreBank("ALBPPLP1BMW")
+		require.NoError(t, err)
+		result, err = testStore.SearchBanks(SearchBanksParams{Query: "alior"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ALBPPLP1BMW"}, swifts(result))
+	})
+	require.NoError(t, testStore.client.FlushDB(testCtx).Err())
+}
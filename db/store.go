@@ -2,109 +2,471 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/grysj/remitly-api/bic"
+	"github.com/grysj/remitly-api/country"
 	"github.com/grysj/remitly-api/parser"
-	"github.com/grysj/remitly-api/util"
+	"github.com/grysj/remitly-api/timezone"
 	"github.com/redis/go-redis/v9"
 )
 
 const bankKeyPrefix = "swiftCode:"
 const iso2IndexKey = "idx:countryISO2"
-const countryCode = "countryISO2:name"
+const branchKeyPrefix = "branch:"
+const countriesKey = "countries"
+
+// revisionSeqKey numbers every write of a bank. It is shared by all dataset
+// versions, so a revision never identifies two different records.
+const revisionSeqKey = "revision:seq"
+
+// writeSeqKey is bumped in the same transaction as every write to a bank
+// record, deletes included. A reader that rebuilds indexes from a scan
+// WATCHes it to notice writes that landed after the scan.
+const writeSeqKey = "write:seq"
+
+var (
+	ErrCountryMismatch  = errors.New("country ISO2 code does not match the SWIFT code")
+	ErrBankNotFound     = errors.New("bank not found")
+	ErrBankExists       = errors.New("a bank with this SWIFT code exists")
+	ErrRevisionMismatch = errors.New("bank revision does not match")
+)
 
-func (s *RedisStore) AddBanksFromCSV(rows []parser.CsvRow) error {
-	ctx := context.Background()
-	pipe := s.client.TxPipeline()
+func bankFromRow(row parser.CsvRow) Bank {
+	return Bank{
+		Swift:    row.Swift,
+		ISO2:     row.ISO2,
+		Name:     row.Name,
+		Type:     row.Type,
+		Address:  row.Address,
+		Town:     row.Town,
+		Country:  row.Country,
+		Timezone: row.Timezone,
+	}
+}
+
+// streetSeparator joins street lines in the single "street" hash field.
+const streetSeparator = "\n"
 
-	for _, row := range rows {
-		bankData := Bank{
-			Swift:      row.Swift,
-			ISO2:       strings.ToUpper(row.ISO2),
-			Name:       strings.ToUpper(row.Name),
-			Type:       row.Type,
-			Address:    row.Address,
-			Town:       row.Town,
-			Country:    row.Country,
-			Timezone:   row.Timezone,
-			Headquater: util.CheckIfHeadquater(row.Swift),
+func formatBank(bank Bank) Bank {
+	address := parser.ParseAddress(bank.Address, bank.Town)
+	zone, err := timezone.Normalize(bank.Timezone)
+	if err != nil {
+		zone = bank.Timezone
+	}
+	iso2 := strings.ToUpper(strings.TrimSpace(bank.ISO2))
+	countryName := bank.Country
+	if registered, ok := country.ByAlpha2(iso2); ok {
+		countryName = registered.DisplayName()
+	}
+	swift := strings.ToUpper(strings.TrimSpace(bank.Swift))
+	code, err := bic.Parse(swift)
+	if err == nil {
+		swift = code.BIC11()
+	}
+	return Bank{
+		Swift:      swift,
+		ISO2:       iso2,
+		Name:       strings.ToUpper(bank.Name),
+		Type:       bank.Type,
+		Address:    bank.Address,
+		Street:     strings.Join(address.StreetLines, streetSeparator),
+		Postcode:   address.Postcode,
+		Region:     address.Region,
+		Town:       bank.Town,
+		Country:    countryName,
+		Timezone:   zone,
+		Headquater: err == nil && code.IsHeadquarters(),
+	}
+}
+
+// branchSetKey returns the set listing the branches of the institution a
+// code belongs to. Headquarters and malformed codes are not listed.
+func branchSetKey(ks keyspace, swift string) (string, bool) {
+	code, err := bic.Parse(swift)
+	if err != nil || code.IsHeadquarters() {
+		return "", false
+	}
+	return ks.branches(code.BIC8()), true
+}
+
+// indexEntry is one member of a secondary index. Entries of sorted sets
+// carry a score.
+type indexEntry struct {
+	key    string
+	member string
+	sorted bool
+	score  float64
+}
+
+// bankIndexEntries lists every secondary index entry of a stored bank. The
+// countries hash is maintained separately.
+func bankIndexEntries(ks keyspace, bank Bank) []indexEntry {
+	bankKey := ks.bank(bank.Swift)
+	entries := []indexEntry{
+		{key: ks.iso2Index(bank.ISO2), member: bankKey},
+		{key: ks.swiftLex(), member: bank.Swift, sorted: true},
+	}
+	if branchKey, ok := branchSetKey(ks, bank.Swift); ok {
+		entries = append(entries, indexEntry{key: branchKey, member: bank.Swift})
+	}
+	if member, ok := nameLexMember(bank); ok {
+		entries = append(entries, indexEntry{key: ks.nameLex(), member: member, sorted: true})
+	}
+	if town := townKey(bank.Town); town != "" {
+		entries = append(entries, indexEntry{key: ks.townIndex(bank.ISO2), member: town + lexSeparator + bank.Swift, sorted: true})
+	}
+	for term, score := range bankTerms(bank) {
+		entries = append(entries, indexEntry{key: ks.terms(term), member: bankKey, sorted: true, score: score})
+	}
+	return entries
+}
+
+// writeBank stores a formatted bank and adds it to every secondary index.
+func writeBank(ctx context.Context, pipe redis.Pipeliner, ks keyspace, bank Bank) {
+	pipe.HSet(ctx, ks.bank(bank.Swift), &bank)
+	writeIndexes(ctx, pipe, ks, bank)
+	pipe.Incr(ctx, writeSeqKey)
+}
+
+func writeIndexes(ctx context.Context, pipe redis.Pipeliner, ks keyspace, bank Bank) {
+	for _, entry := range bankIndexEntries(ks, bank) {
+		if entry.sorted {
+			pipe.ZAdd(ctx, entry.key, redis.Z{Score: entry.score, Member: entry.member})
+		} else {
+			pipe.SAdd(ctx, entry.key, entry.member)
 		}
+	}
+	if bank.Country != "" {
+		pipe.HSet(ctx, ks.countries(), bank.ISO2, bank.Country)
+	}
+}
+
+// removeBank deletes a bank together with its secondary index entries.
+// Country names are cleaned up separately, by emptiedCountries inside the
+// transaction or by pruneCountries once the pipeline has run.
+func removeBank(ctx context.Context, pipe redis.Pipeliner, ks keyspace, bank Bank) {
+	pipe.Del(ctx, ks.bank(bank.Swift))
+	for _, entry := range bankIndexEntries(ks, bank) {
+		if entry.sorted {
+			pipe.ZRem(ctx, entry.key, entry.member)
+		} else {
+			pipe.SRem(ctx, entry.key, entry.member)
+		}
+	}
+	pipe.Incr(ctx, writeSeqKey)
+}
 
-		bankKey := bankKeyPrefix + row.Swift
-		pipe.HSet(ctx, bankKey, &bankData)
-		pipe.SAdd(ctx, iso2IndexKey+":"+row.ISO2, bankKey)
+func StreetLines(street string) []string {
+	if street == "" {
+		return []string{}
+	}
+	return strings.Split(street, streetSeparator)
+}
 
-		if !util.CheckIfHeadquater(row.Swift) {
-			pipe.SAdd(ctx, "branch:"+util.GetPrefix(row.Swift), row.Swift)
+func (s *RedisStore) pruneCountries(ctx context.Context, ks keyspace, iso2Codes map[string]bool) error {
+	for iso2 := range iso2Codes {
+		count, err := s.client.SCard(ctx, ks.iso2Index(iso2)).Result()
+		if err != nil {
+			return fmt.Errorf("failed to count banks for ISO2 %s: %w", iso2, err)
+		}
+		if count == 0 {
+			if err := s.client.HDel(ctx, ks.countries(), iso2).Err(); err != nil {
+				return fmt.Errorf("failed to remove country %s: %w", iso2, err)
+			}
 		}
-		pipe.HSet(ctx, "countries", strings.ToUpper(row.ISO2), row.Country)
 	}
+	return nil
+}
 
-	_, err := pipe.Exec(ctx)
-	return err
+// emptiedCountries returns the countries left without banks once the given
+// stored banks are removed. Their index sets are watched, so a concurrent
+// write to one of these countries makes the transaction retry.
+func emptiedCountries(ctx context.Context, tx *redis.Tx, ks keyspace, banks []Bank) ([]string, error) {
+	removed := make(map[string]int64)
+	for _, bank := range banks {
+		removed[bank.ISO2]++
+	}
+	var emptied []string
+	for iso2, count := range removed {
+		key := ks.iso2Index(iso2)
+		if err := tx.Watch(ctx, key).Err(); err != nil {
+			return nil, fmt.Errorf("failed to watch banks for ISO2 %s: %w", iso2, err)
+		}
+		stored, err := tx.SCard(ctx, key).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to count banks for ISO2 %s: %w", iso2, err)
+		}
+		if stored <= count {
+			emptied = append(emptied, iso2)
+		}
+	}
+	return emptied, nil
+}
+
+// nextRevisions reserves n revisions and returns the first one.
+func (s *RedisStore) nextRevisions(ctx context.Context, n int) (int64, error) {
+	last, err := s.client.IncrBy(ctx, revisionSeqKey, int64(n)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to allocate revision: %w", err)
+	}
+	return last - int64(n) + 1, nil
+}
+
+// checkRevision enforces the precondition of a conditional write: the
+// revision it expects, or with mustExist only that the record is stored.
+// Zero without mustExist means the write is unconditional.
+func checkRevision(expected int64, mustExist bool, bank *Bank) error {
+	if bank == nil {
+		if expected != 0 || mustExist {
+			return ErrRevisionMismatch
+		}
+		return nil
+	}
+	if expected != 0 && bank.Revision != expected {
+		return ErrRevisionMismatch
+	}
+	return nil
 }
 
-func (s *RedisStore) AddBankToDB(bank Bank) error {
+// AddBanksFromCSV overlays rows on the active dataset version in one
+// transaction. Use ReloadDataset to replace the dataset instead.
+func (s *RedisStore) AddBanksFromCSV(rows []parser.CsvRow) error {
 	ctx := context.Background()
-	pipe := s.client.TxPipeline()
+	return s.update(ctx, func(tx *redis.Tx, ks keyspace) error {
+		banks := make([]Bank, 0, len(rows))
+		previous := make(map[string]*Bank, len(rows))
+		for _, row := range rows {
+			bank := formatBank(bankFromRow(row))
+			banks = append(banks, bank)
+			if _, ok := previous[bank.Swift]; ok {
+				continue
+			}
+			old, err := storedBank(ctx, tx, ks, bank.Swift)
+			if err != nil {
+				return err
+			}
+			previous[bank.Swift] = old
+		}
+
+		revision, err := s.nextRevisions(ctx, len(banks))
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			for i, bank := range banks {
+				bank.Revision = revision + int64(i)
+				if old := previous[bank.Swift]; old != nil {
+					removeBank(ctx, pipe, ks, *old)
+				}
+				writeBank(ctx, pipe, ks, bank)
+				s.recordChange(ctx, pipe, ks, SourceImport, previous[bank.Swift], &bank)
+				previous[bank.Swift] = &bank
+			}
+			return nil
+		})
+		return err
+	})
+}
 
+func validateBank(bank Bank) error {
 	if len(bank.ISO2) != 2 {
 		return fmt.Errorf("invalid ISO2 format: must be exactly 2 letters")
 	}
-	if bank.Country == "" {
-		return fmt.Errorf("country name cannot be empty")
+	if _, ok := country.ByAlpha2(bank.ISO2); !ok {
+		return fmt.Errorf("unknown country code %q", bank.ISO2)
 	}
-
-	formattedBank := Bank{
-		Swift:      bank.Swift,
-		ISO2:       strings.ToUpper(bank.ISO2),
-		Name:       strings.ToUpper(bank.Name),
-		Type:       bank.Type,
-		Address:    bank.Address,
-		Town:       bank.Town,
-		Country:    bank.Country,
-		Timezone:   bank.Timezone,
-		Headquater: util.CheckIfHeadquater(bank.Swift),
+	code, err := bic.Parse(bank.Swift)
+	if err != nil {
+		return fmt.Errorf("invalid SWIFT code: %w", err)
+	}
+	if !code.MatchesCountry(bank.ISO2) {
+		return ErrCountryMismatch
+	}
+	if _, err := timezone.Normalize(bank.Timezone); err != nil {
+		return fmt.Errorf("invalid time zone: %w", err)
 	}
+	return nil
+}
 
-	bankKey := bankKeyPrefix + bank.Swift
-	pipe.HSet(ctx, bankKey, &formattedBank)
-	pipe.SAdd(ctx, iso2IndexKey+":"+formattedBank.ISO2, bankKey)
-	if !util.CheckIfHeadquater(bank.Swift) {
-		pipe.SAdd(ctx, "branch:"+util.GetPrefix(bank.Swift), bank.Swift)
+// AddBankToDB creates a bank and returns the stored record. It fails with
+// ErrBankExists if the code is already stored; use UpdateBank to change an
+// existing bank.
+func (s *RedisStore) AddBankToDB(bank Bank) (*Bank, error) {
+	ctx := context.Background()
+
+	if err := validateBank(bank); err != nil {
+		return nil, err
 	}
-	pipe.HSet(ctx, "countries", strings.ToUpper(bank.ISO2), bank.Country)
 
-	_, err := pipe.Exec(ctx)
-	return err
+	formatted := formatBank(bank)
+	err := s.update(ctx, func(tx *redis.Tx, ks keyspace) error {
+		if err := tx.Watch(ctx, ks.bank(formatted.Swift)).Err(); err != nil {
+			return fmt.Errorf("failed to watch bank: %w", err)
+		}
+		exists, err := tx.Exists(ctx, ks.bank(formatted.Swift)).Result()
+		if err != nil {
+			return fmt.Errorf("failed to check bank existence: %w", err)
+		}
+		if exists > 0 {
+			return ErrBankExists
+		}
+		formatted.Revision, err = s.nextRevisions(ctx, 1)
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			writeBank(ctx, pipe, ks, formatted)
+			s.recordChange(ctx, pipe, ks, SourceAPI, nil, &formatted)
+			return nil
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &formatted, nil
 }
 
-func (s *RedisStore) DeleteBankFromDB(bank DeleteBankParams) error {
+// UpdateBank replaces every field of a stored bank and returns the stored
+// record. Index entries of the old record are removed first. The country
+// must match the SWIFT code, so it only changes when a record stored with a
+// mismatched country is corrected; the old country name is then dropped
+// once no bank uses it.
+func (s *RedisStore) UpdateBank(params UpdateBankParams) (*Bank, error) {
 	ctx := context.Background()
-	pipe := s.client.TxPipeline()
 
-	bankKey := bankKeyPrefix + bank.Swift
-	bankData := &Bank{}
-	err := s.client.HGetAll(ctx, bankKey).Scan(bankData)
+	if err := validateBank(params.Bank); err != nil {
+		return nil, err
+	}
+
+	formatted := formatBank(params.Bank)
+	var old *Bank
+	var applied keyspace
+	err := s.update(ctx, func(tx *redis.Tx, ks keyspace) error {
+		var err error
+		applied = ks
+		if err := tx.Watch(ctx, ks.bank(formatted.Swift)).Err(); err != nil {
+			return fmt.Errorf("failed to watch bank: %w", err)
+		}
+		old, err = storedBank(ctx, tx, ks, formatted.Swift)
+		if err != nil {
+			return err
+		}
+		if err := checkRevision(params.Revision, params.MustExist, old); err != nil {
+			return err
+		}
+		if old == nil {
+			return ErrBankNotFound
+		}
+		formatted.Revision, err = s.nextRevisions(ctx, 1)
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			removeBank(ctx, pipe, ks, *old)
+			writeBank(ctx, pipe, ks, formatted)
+			s.recordChange(ctx, pipe, ks, SourceAPI, old, &formatted)
+			return nil
+		})
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("failed to get bank data: %w", err)
+		return nil, err
 	}
 
-	pipe.Del(ctx, bankKey)
-	pipe.SRem(ctx, iso2IndexKey+":"+bankData.ISO2, bankKey)
+	if old.ISO2 != formatted.ISO2 {
+		if err := s.pruneCountries(ctx, applied, map[string]bool{old.ISO2: true}); err != nil {
+			return &formatted, err
+		}
+	}
+	return &formatted, nil
+}
 
-	_, err = pipe.Exec(ctx)
-	return err
+// GetBank returns every stored field of a bank, or nil if the code is not
+// stored.
+func (s *RedisStore) GetBank(swift string) (*Bank, error) {
+	ctx := context.Background()
+	ks, err := s.active(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := s.client.HGetAll(ctx, ks.bank(swift))
+	if err := cmd.Err(); err != nil {
+		return nil, fmt.Errorf("failed to retrieve bank data: %w", err)
+	}
+	if len(cmd.Val()) == 0 {
+		return nil, nil
+	}
+	var bank Bank
+	if err := cmd.Scan(&bank); err != nil {
+		return nil, fmt.Errorf("failed to parse bank data: %w", err)
+	}
+	return &bank, nil
+}
+
+// DeleteBankFromDB soft-deletes a code. With Cascade, deleting a
+// headquarters also deletes its branches.
+func (s *RedisStore) DeleteBankFromDB(bank DeleteBankParams) error {
+	if bank.Cascade {
+		if code, err := bic.Parse(bank.Swift); err == nil && code.IsHeadquarters() {
+			return s.deleteInstitution(code, bank.Revision, bank.MustExist)
+		}
+	}
+
+	ctx := context.Background()
+	if err := s.purgeActiveDeleted(ctx); err != nil {
+		return err
+	}
+	return s.update(ctx, func(tx *redis.Tx, ks keyspace) error {
+		if err := tx.Watch(ctx, ks.bank(bank.Swift)).Err(); err != nil {
+			return fmt.Errorf("failed to watch bank: %w", err)
+		}
+		bankData, err := storedBank(ctx, tx, ks, bank.Swift)
+		if err != nil {
+			return err
+		}
+		if err := checkRevision(bank.Revision, bank.MustExist, bankData); err != nil {
+			return err
+		}
+		if bankData == nil {
+			return nil
+		}
+		emptied, err := emptiedCountries(ctx, tx, ks, []Bank{*bankData})
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			s.softDelete(ctx, pipe, ks, *bankData, "", time.Now())
+			if len(emptied) > 0 {
+				pipe.HDel(ctx, ks.countries(), emptied...)
+			}
+			return nil
+		})
+		return err
+	})
 }
 
 func (s *RedisStore) GetBanksByISO2(iso2 string) ([]GetBankByIsoResult, error) {
 	ctx := context.Background()
-	bankKeys, err := s.client.SMembers(ctx, iso2IndexKey+":"+strings.ToUpper(iso2)).Result()
+	ks, err := s.active(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bankKeys, err := s.client.SMembers(ctx, ks.iso2Index(strings.ToUpper(iso2))).Result()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get bank keys for ISO2 %s: %w", iso2, err)
 	}
 
+	return s.banksFromKeys(ctx, bankKeys)
+}
+
+func (s *RedisStore) banksFromKeys(ctx context.Context, bankKeys []string) ([]GetBankByIsoResult, error) {
 	if len(bankKeys) == 0 {
 		return []GetBankByIsoResult{}, nil
 	}
@@ -115,7 +477,7 @@ func (s *RedisStore) GetBanksByISO2(iso2 string) ([]GetBankByIsoResult, error) {
 		cmds[i] = pipe.HGetAll(ctx, key)
 	}
 
-	_, err = pipe.Exec(ctx)
+	_, err := pipe.Exec(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get bank data: %w", err)
 	}
@@ -133,7 +495,15 @@ func (s *RedisStore) GetBanksByISO2(iso2 string) ([]GetBankByIsoResult, error) {
 
 func (s *RedisStore) GetBankBranches(swift string) ([]GetBranchesBySwiftResult, error) {
 	ctx := context.Background()
-	branchSet := "branch:" + util.GetPrefix(swift)
+	code, err := bic.Parse(swift)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SWIFT code: %w", err)
+	}
+	ks, err := s.active(ctx)
+	if err != nil {
+		return nil, err
+	}
+	branchSet := ks.branches(code.BIC8())
 
 	exists, err := s.client.Exists(ctx, branchSet).Result()
 	if err != nil {
@@ -155,7 +525,7 @@ func (s *RedisStore) GetBankBranches(swift string) ([]GetBranchesBySwiftResult,
 	pipe := s.client.Pipeline()
 	cmds := make([]*redis.MapStringStringCmd, len(branchSwifts))
 	for i, branchSwift := range branchSwifts {
-		cmds[i] = pipe.HGetAll(ctx, bankKeyPrefix+branchSwift)
+		cmds[i] = pipe.HGetAll(ctx, ks.bank(branchSwift))
 	}
 
 	_, err = pipe.Exec(ctx)
@@ -176,7 +546,14 @@ func (s *RedisStore) GetBankBranches(swift string) ([]GetBranchesBySwiftResult,
 
 func (s *RedisStore) GetBankFromSwift(swift string) (*GetBankBySwiftResult, error) {
 	ctx := context.Background()
-	bankKey := bankKeyPrefix + strings.ToUpper(swift)
+	ks, err := s.active(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bankKey := ks.bank(strings.ToUpper(swift))
+	if code, err := bic.Parse(swift); err == nil {
+		bankKey = ks.bank(code.BIC11())
+	}
 
 	exists, err := s.client.Exists(ctx, bankKey).Result()
 	if err != nil {
@@ -200,43 +577,92 @@ func (s *RedisStore) GetBankFromSwift(swift string) (*GetBankBySwiftResult, erro
 	return &bank, nil
 }
 
-func (s *RedisStore) DeleteBanksBySwiftPrefix(swiftPrefix string) error {
-	ctx := context.Background()
-	hqKey := bankKeyPrefix + swiftPrefix + "XXX"
-	var hqBank Bank
-	err := s.client.HGetAll(ctx, hqKey).Scan(&hqBank)
-	if err != nil && err != redis.Nil {
-		return fmt.Errorf("failed to get headquarters info: %w", err)
-	}
+// DeleteBanksBySwiftPrefix soft-deletes the institution code belongs to:
+// its headquarters and every branch.
+func (s *RedisStore) DeleteBanksBySwiftPrefix(code bic.BIC) error {
+	return s.deleteInstitution(code, 0, false)
+}
 
-	pipe := s.client.Pipeline()
+// deleteInstitution soft-deletes a headquarters and all its branches. A
+// non-zero revision must match the headquarters record, which mustExist
+// requires to be stored.
+func (s *RedisStore) deleteInstitution(code bic.BIC, revision int64, mustExist bool) error {
+	ctx := context.Background()
+	if err := s.purgeActiveDeleted(ctx); err != nil {
+		return err
+	}
+	hqSwift := code.Headquarters().BIC11()
+	var hqKey string
+	err := s.update(ctx, func(tx *redis.Tx, ks keyspace) error {
+		hqKey = ks.bank(hqSwift)
+		branchSetKey := ks.branches(code.BIC8())
+		if err := tx.Watch(ctx, hqKey, branchSetKey).Err(); err != nil {
+			return fmt.Errorf("failed to watch headquarters: %w", err)
+		}
+		var hqBank Bank
+		err := tx.HGetAll(ctx, hqKey).Scan(&hqBank)
+		if err != nil && err != redis.Nil {
+			return fmt.Errorf("failed to get headquarters info: %w", err)
+		}
+		var stored *Bank
+		if hqBank.ISO2 != "" {
+			stored = &hqBank
+		}
+		if err := checkRevision(revision, mustExist, stored); err != nil {
+			return err
+		}
 
-	if hqBank.ISO2 != "" {
-		pipe.SRem(ctx, iso2IndexKey+":"+hqBank.ISO2, hqKey)
-		pipe.Del(ctx, hqKey)
-	}
+		branchSwifts, err := tx.SMembers(ctx, branchSetKey).Result()
+		if err != nil && err != redis.Nil {
+			return fmt.Errorf("failed to get branch members: %w", err)
+		}
 
-	branchSetKey := "branch:" + swiftPrefix
-	branchSwifts, err := s.client.SMembers(ctx, branchSetKey).Result()
-	if err != nil && err != redis.Nil {
-		return fmt.Errorf("failed to get branch members: %w", err)
-	}
+		branches := make([]Bank, 0, len(branchSwifts))
+		for _, swift := range branchSwifts {
+			var branch Bank
+			if err := tx.HGetAll(ctx, ks.bank(swift)).Scan(&branch); err != nil {
+				return fmt.Errorf("failed to get branch info: %w", err)
+			}
+			// a member whose record is gone is dropped with the set, not
+			// tombstoned
+			if branch.ISO2 == "" {
+				continue
+			}
+			branch.Swift = swift
+			branches = append(branches, branch)
+		}
 
-	for _, swift := range branchSwifts {
-		bankKey := bankKeyPrefix + swift
-		var branch Bank
-		err := s.client.HGetAll(ctx, bankKey).Scan(&branch)
-		if err == nil && branch.ISO2 != "" {
-			pipe.SRem(ctx, iso2IndexKey+":"+branch.ISO2, bankKey)
+		removed := branches
+		if hqBank.ISO2 != "" {
+			hqBank.Swift = hqSwift
+			removed = append([]Bank{hqBank}, branches...)
+		}
+		emptied, err := emptiedCountries(ctx, tx, ks, removed)
+		if err != nil {
+			return err
 		}
-		pipe.Del(ctx, bankKey)
-	}
 
-	if len(branchSwifts) > 0 {
-		pipe.Del(ctx, branchSetKey)
+		now := time.Now()
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			if hqBank.ISO2 != "" {
+				s.softDelete(ctx, pipe, ks, hqBank, "", now)
+			}
+			for _, branch := range branches {
+				s.softDelete(ctx, pipe, ks, branch, hqSwift, now)
+			}
+			if len(branchSwifts) > 0 {
+				pipe.Del(ctx, branchSetKey)
+			}
+			if len(emptied) > 0 {
+				pipe.HDel(ctx, ks.countries(), emptied...)
+			}
+			return nil
+		})
+		return err
+	})
+	if errors.Is(err, ErrRevisionMismatch) {
+		return err
 	}
-
-	_, err = pipe.Exec(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to execute deletion pipeline: %w", err)
 	}
@@ -253,8 +679,17 @@ func (s *RedisStore) DeleteBanksBySwiftPrefix(swiftPrefix string) error {
 }
 
 func (s *RedisStore) GetCountryNameByISO2(iso2 string) (string, error) {
+	if registered, ok := country.ByAlpha2(iso2); ok {
+		return registered.DisplayName(), nil
+	}
+
+	// codes outside the registry can only come from data stored before it existed
 	ctx := context.Background()
-	countryName, err := s.client.HGet(ctx, "countries", strings.ToUpper(iso2)).Result()
+	ks, err := s.active(ctx)
+	if err != nil {
+		return "", err
+	}
+	countryName, err := s.client.HGet(ctx, ks.countries(), strings.ToUpper(iso2)).Result()
 	if err == redis.Nil {
 		return "", nil
 	}
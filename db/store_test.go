@@ -4,6 +4,7 @@ import (
 	"sort"
 	"testing"
 
+	"github.com/grysj/remitly-api/bic"
 	"github.com/grysj/remitly-api/parser"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -52,6 +53,13 @@ func TestAddBanksToRedis(t *testing.T) {
 				assert.Equal(t, "AL", bankData.ISO2)
 				assert.Equal(t, "UNITED BANK OF ALBANIA SH.A", bankData.Name)
 
+				var stored Bank
+				err = testStore.client.HGetAll(testCtx, "swiftCode:AAISALTRXXX").Scan(&stored)
+				require.NoError(t, err)
+				assert.Equal(t, "HYRJA 3 RR. DRITAN HOXHA ND. 11", stored.Street)
+				assert.Equal(t, "1023", stored.Postcode)
+				assert.Equal(t, "TIRANA", stored.Town)
+
 				members, err := testStore.client.SMembers(testCtx, "idx:countryISO2:AL").Result()
 				require.NoError(t, err)
 				assert.Contains(t, members, "swiftCode:AAISALTRXXX")
@@ -207,6 +215,65 @@ func TestAddBankToRedis(t *testing.T) {
 				assert.Contains(t, keys, "swiftCode:ABIEBGS1XXX")
 			},
 		},
+		{
+			name: "deprecated_timezone_is_normalized",
+			bank: Bank{
+				ISO2:     "UA",
+				Swift:    "AAAAUAUKXXX",
+				Name:     "Test Bank",
+				Country:  "UKRAINE",
+				Timezone: "Europe/Kiev",
+			},
+			verify: func(t *testing.T, _ Bank) {
+				zone, err := testStore.client.HGet(testCtx, "swiftCode:AAAAUAUKXXX", "timezone").Result()
+				require.NoError(t, err)
+				assert.Equal(t, "Europe/Kyiv", zone)
+			},
+		},
+		{
+			name: "country_name_from_registry",
+			bank: Bank{
+				ISO2:    "pl",
+				Swift:   "ALBPPLPWXXX",
+				Name:    "Test Bank",
+				Country: "Polska",
+			},
+			verify: func(t *testing.T, _ Bank) {
+				bankData := &Bank{}
+				err := testStore.client.HGetAll(testCtx, "swiftCode:ALBPPLPWXXX").Scan(bankData)
+				require.NoError(t, err)
+				assert.Equal(t, "POLAND", bankData.Country)
+				name, err := testStore.client.HGet(testCtx, "countries", "PL").Result()
+				require.NoError(t, err)
+				assert.Equal(t, "POLAND", name)
+			},
+		},
+		{
+			name: "unknown_country_code",
+			bank: Bank{
+				ISO2:    "ZZ",
+				Swift:   "AAAAZZZZXXX",
+				Name:    "Test Bank",
+				Country: "NOWHERE",
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown_timezone",
+			bank: Bank{
+				ISO2:     "UA",
+				Swift:    "AAAAUAUKXXX",
+				Name:     "Test Bank",
+				Country:  "UKRAINE",
+				Timezone: "Europe/Atlantis",
+			},
+			wantErr: true,
+			verify: func(t *testing.T, _ Bank) {
+				exists, err := testStore.client.Exists(testCtx, "swiftCode:AAAAUAUKXXX").Result()
+				require.NoError(t, err)
+				assert.Zero(t, exists)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -214,7 +281,7 @@ func TestAddBankToRedis(t *testing.T) {
 			err := testStore.client.FlushDB(testCtx).Err()
 			require.NoError(t, err)
 
-			err = testStore.AddBankToDB(tt.bank)
+			_, err = testStore.AddBankToDB(tt.bank)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -250,7 +317,7 @@ func TestDeleteBankFromRedis(t *testing.T) {
 					Country:  "ALBANIA",
 					Timezone: "Europe/Tirane",
 				}
-				err := testStore.AddBankToDB(bank)
+				_, err := testStore.AddBankToDB(bank)
 				require.NoError(t, err)
 			},
 			bank: DeleteBankParams{
@@ -293,7 +360,7 @@ func TestDeleteBankFromRedis(t *testing.T) {
 					},
 				}
 				for _, bank := range banks {
-					err := testStore.AddBankToDB(bank)
+					_, err := testStore.AddBankToDB(bank)
 					require.NoError(t, err)
 				}
 			},
@@ -500,7 +567,7 @@ func TestGetBankBranches(t *testing.T) {
 					Country:  "POLAND",
 					Timezone: "Europe/Warsaw",
 				}
-				err := testStore.AddBankToDB(headOffice)
+				_, err := testStore.AddBankToDB(headOffice)
 				require.NoError(t, err)
 
 				branches := []Bank{
@@ -526,7 +593,7 @@ func TestGetBankBranches(t *testing.T) {
 					},
 				}
 				for _, branch := range branches {
-					err := testStore.AddBankToDB(branch)
+					_, err := testStore.AddBankToDB(branch)
 					require.NoError(t, err)
 				}
 
@@ -567,7 +634,7 @@ func TestGetBankBranches(t *testing.T) {
 					Country:  "ALBANIA",
 					Timezone: "Europe/Tirane",
 				}
-				err := testStore.AddBankToDB(bank)
+				_, err := testStore.AddBankToDB(bank)
 				require.NoError(t, err)
 			},
 			want:    []GetBranchesBySwiftResult{},
@@ -634,7 +701,7 @@ func TestGetBank(t *testing.T) {
 					Country:  "ALBANIA",
 					Timezone: "Europe/Tirane",
 				}
-				err := testStore.AddBankToDB(bank)
+				_, err := testStore.AddBankToDB(bank)
 				require.NoError(t, err)
 			},
 			want: &GetBankBySwiftResult{
@@ -642,6 +709,9 @@ func TestGetBank(t *testing.T) {
 				ISO2:       "AL",
 				Name:       "UNITED BANK OF ALBANIA SH.A",
 				Address:    "HYRJA 3 RR. DRITAN HOXHA ND. 11 TIRANA, TIRANA, 1023",
+				Street:     "HYRJA 3 RR. DRITAN HOXHA ND. 11",
+				Postcode:   "1023",
+				Town:       "TIRANA",
 				Country:    "ALBANIA",
 				Headquater: true,
 			},
@@ -661,7 +731,7 @@ func TestGetBank(t *testing.T) {
 					Country:  "POLAND",
 					Timezone: "Europe/Warsaw",
 				}
-				err := testStore.AddBankToDB(headOffice)
+				_, err := testStore.AddBankToDB(headOffice)
 				require.NoError(t, err)
 
 				branch := Bank{
@@ -674,7 +744,7 @@ func TestGetBank(t *testing.T) {
 					Country:  "POLAND",
 					Timezone: "Europe/Warsaw",
 				}
-				err = testStore.AddBankToDB(branch)
+				_, err = testStore.AddBankToDB(branch)
 				require.NoError(t, err)
 			},
 			want: &GetBankBySwiftResult{
@@ -682,6 +752,8 @@ func TestGetBank(t *testing.T) {
 				ISO2:       "PL",
 				Name:       "ALIOR BANK BRANCH 1",
 				Address:    "Branch Address 1",
+				Street:     "Branch Address 1",
+				Town:       "WARSZAWA",
 				Country:    "POLAND",
 				Headquater: false,
 			},
@@ -708,7 +780,7 @@ func TestGetBank(t *testing.T) {
 					Country:  "ALBANIA",
 					Timezone: "Europe/Tirane",
 				}
-				err := testStore.AddBankToDB(bank)
+				_, err := testStore.AddBankToDB(bank)
 				require.NoError(t, err)
 			},
 			want: &GetBankBySwiftResult{
@@ -716,6 +788,9 @@ func TestGetBank(t *testing.T) {
 				ISO2:       "AL",
 				Name:       "UNITED BANK OF ALBANIA SH.A",
 				Address:    "HYRJA 3 RR. DRITAN HOXHA ND. 11 TIRANA, TIRANA, 1023",
+				Street:     "HYRJA 3 RR. DRITAN HOXHA ND. 11",
+				Postcode:   "1023",
+				Town:       "TIRANA",
 				Country:    "ALBANIA",
 				Headquater: true,
 			},
@@ -748,7 +823,7 @@ func TestGetBank(t *testing.T) {
 					},
 				}
 				for _, bank := range banks {
-					err := testStore.AddBankToDB(bank)
+					_, err := testStore.AddBankToDB(bank)
 					require.NoError(t, err)
 				}
 			},
@@ -757,6 +832,9 @@ func TestGetBank(t *testing.T) {
 				ISO2:       "MC",
 				Name:       "BANK JULIUS BAER (MONACO) S.A.M.",
 				Address:    "12 BOULEVARD DES MOULINS  MONACO, MONACO, 98000",
+				Street:     "12 BOULEVARD DES MOULINS",
+				Postcode:   "98000",
+				Town:       "MONACO",
 				Country:    "MONACO",
 				Headquater: true,
 			},
@@ -764,11 +842,11 @@ func TestGetBank(t *testing.T) {
 		},
 		{
 			name:  "bank_with_optional_fields",
-			swift: "TESTBANK1XXX",
+			swift: "TESTUSNYXXX",
 			setup: func(t *testing.T) {
 				bank := Bank{
 					ISO2:     "US",
-					Swift:    "TESTBANK1XXX",
+					Swift:    "TESTUSNYXXX",
 					Type:     "BIC11",
 					Name:     "TEST BANK",
 					Address:  "",
@@ -776,11 +854,11 @@ func TestGetBank(t *testing.T) {
 					Country:  "UNITED STATES",
 					Timezone: "",
 				}
-				err := testStore.AddBankToDB(bank)
+				_, err := testStore.AddBankToDB(bank)
 				require.NoError(t, err)
 			},
 			want: &GetBankBySwiftResult{
-				Swift:      "TESTBANK1XXX",
+				Swift:      "TESTUSNYXXX",
 				ISO2:       "US",
 				Name:       "TEST BANK",
 				Address:    "",
@@ -808,6 +886,10 @@ func TestGetBank(t *testing.T) {
 			}
 			require.NoError(t, err)
 
+			if got != nil {
+				assert.NotZero(t, got.Revision, "every write assigns a revision")
+				got.Revision = 0
+			}
 			assert.Equal(t, tt.want, got)
 		})
 	}
@@ -841,6 +923,16 @@ func TestGetCountryNameByISO2(t *testing.T) {
 			want:    "MONACO",
 			wantErr: false,
 		},
+		{
+			name: "registry_name_wins_over_stored_name",
+			iso2: "PL",
+			setup: func(t *testing.T) {
+				err := testStore.client.HSet(testCtx, "countries", "PL", "POLSKA").Err()
+				require.NoError(t, err)
+			},
+			want:    "POLAND",
+			wantErr: false,
+		},
 		{
 			name:    "nonexistent_country",
 			iso2:    "XX",
@@ -925,7 +1017,7 @@ func TestDeleteBanksBySwiftPrefix(t *testing.T) {
 					Country:  "CHILE",
 					Timezone: "Pacific/Easter",
 				}
-				err := testStore.AddBankToDB(hq)
+				_, err := testStore.AddBankToDB(hq)
 				require.NoError(t, err)
 
 				branches := []Bank{
@@ -952,7 +1044,7 @@ func TestDeleteBanksBySwiftPrefix(t *testing.T) {
 				}
 
 				for _, branch := range branches {
-					err := testStore.AddBankToDB(branch)
+					_, err := testStore.AddBankToDB(branch)
 					require.NoError(t, err)
 				}
 			},
@@ -1001,7 +1093,7 @@ func TestDeleteBanksBySwiftPrefix(t *testing.T) {
 					Country:  "MONACO",
 					Timezone: "Europe/Monaco",
 				}
-				err := testStore.AddBankToDB(hq)
+				_, err := testStore.AddBankToDB(hq)
 				require.NoError(t, err)
 			},
 			verify: func(t *testing.T) {
@@ -1040,7 +1132,9 @@ func TestDeleteBanksBySwiftPrefix(t *testing.T) {
 				tt.setup(t)
 			}
 
-			err = testStore.DeleteBanksBySwiftPrefix(tt.swiftPrefix)
+			code, err := bic.Parse(tt.swiftPrefix)
+			require.NoError(t, err)
+			err = testStore.DeleteBanksBySwiftPrefix(code)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -1054,3 +1148,79 @@ func TestDeleteBanksBySwiftPrefix(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateBank(t *testing.T) {
+	require.NoError(t, testStore.client.FlushDB(testCtx).Err())
+
+	_, err := testStore.AddBankToDB(Bank{ISO2: "MT", Swift: "AKBKMTMT001", Name: "AKBANK BRANCH", Country: "MALTA", Address: "VALLETTA"})
+	require.NoError(t, err)
+	_, err = testStore.AddBankToDB(Bank{ISO2: "MT", Swift: "AKBKMTMT001", Name: "AKBANK", Country: "MALTA"})
+	assert.ErrorIs(t, err, ErrBankExists)
+
+	created, err := testStore.GetBank("AKBKMTMT001")
+	require.NoError(t, err)
+	require.NotNil(t, created)
+	assert.NotZero(t, created.Revision)
+
+	_, err = testStore.UpdateBank(UpdateBankParams{Bank: Bank{ISO2: "MT", Swift: "AKBKMTMT001", Name: "AKBANK"}, Revision: created.Revision + 1})
+	assert.ErrorIs(t, err, ErrRevisionMismatch)
+
+	_, err = testStore.UpdateBank(UpdateBankParams{Bank: Bank{ISO2: "PL", Swift: "AKBKMTMT001", Name: "AKBANK"}, Revision: created.Revision})
+	assert.ErrorIs(t, err, ErrCountryMismatch)
+
+	updated, err := testStore.UpdateBank(UpdateBankParams{Bank: Bank{ISO2: "mt", Swift: "AKBKMTMT001", Name: "akbank branch", Town: "SLIEMA"}, Revision: created.Revision})
+	require.NoError(t, err)
+	assert.Greater(t, updated.Revision, created.Revision)
+
+	bank, err := testStore.GetBank("AKBKMTMT001")
+	require.NoError(t, err)
+	require.NotNil(t, bank)
+	assert.Equal(t, updated.Revision, bank.Revision)
+	assert.Equal(t, "AKBANK BRANCH", bank.Name)
+	assert.Equal(t, "MT", bank.ISO2)
+	assert.Equal(t, "MALTA", bank.Country)
+	assert.Equal(t, "SLIEMA", bank.Town)
+	assert.Empty(t, bank.Address)
+
+	malta, err := testStore.client.SMembers(testCtx, "idx:countryISO2:MT").Result()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"swiftCode:AKBKMTMT001"}, malta)
+	towns, err := testStore.client.ZRange(testCtx, "idx:town:MT", 0, -1).Result()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"SLIEMA\x00AKBKMTMT001"}, towns, "the old record's index entries are removed")
+
+	countries, err := testStore.client.HGetAll(testCtx, "countries").Result()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"MT": "MALTA"}, countries)
+
+	branches, err := testStore.client.SMembers(testCtx, "branch:AKBKMTMT").Result()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"AKBKMTMT001"}, branches)
+
+	_, err = testStore.UpdateBank(UpdateBankParams{Bank: Bank{ISO2: "PL", Swift: "ALBPPLP1BMW", Name: "ALIOR BANK"}})
+	assert.ErrorIs(t, err, ErrBankNotFound)
+	_, err = testStore.UpdateBank(UpdateBankParams{Bank: Bank{ISO2: "PL", Swift: "ALBPPLP1BMW", Name: "ALIOR BANK"}, Revision: 1})
+	assert.ErrorIs(t, err, ErrRevisionMismatch)
+
+	_, err = testStore.UpdateBank(UpdateBankParams{Bank: Bank{ISO2: "PL", Swift: "ALBPPLP1BMW", Name: "ALIOR BANK"}, MustExist: true})
+	assert.ErrorIs(t, err, ErrRevisionMismatch)
+
+	assert.ErrorIs(t, testStore.DeleteBankFromDB(DeleteBankParams{Swift: "AKBKMTMT001", Revision: created.Revision}), ErrRevisionMismatch)
+	require.NoError(t, testStore.DeleteBankFromDB(DeleteBankParams{Swift: "AKBKMTMT001", Revision: bank.Revision}))
+
+	// a record deleted after the caller checked that it exists
+	assert.ErrorIs(t, testStore.DeleteBankFromDB(DeleteBankParams{Swift: "AKBKMTMT001", MustExist: true}), ErrRevisionMismatch)
+	assert.ErrorIs(t, testStore.DeleteBankFromDB(DeleteBankParams{Swift: "AKBKMTMTXXX", MustExist: true, Cascade: true}), ErrRevisionMismatch)
+	_, err = testStore.UpdateBank(UpdateBankParams{Bank: Bank{ISO2: "MT", Swift: "AKBKMTMT001", Name: "AKBANK"}, MustExist: true})
+	assert.ErrorIs(t, err, ErrRevisionMismatch)
+	missing, err := testStore.GetBank("AKBKMTMT001")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+	deleted, err := testStore.ListDeletedBanks()
+	require.NoError(t, err)
+	assert.Len(t, deleted, 1)
+
+	_, err = testStore.AddBankToDB(Bank{ISO2: "MT", Swift: "AKBKMTMT001", Name: "AKBANK BRANCH", Country: "MALTA"})
+	require.NoError(t, err)
+	require.NoError(t, testStore.DeleteBankFromDB(DeleteBankParams{Swift: "AKBKMTMT001", MustExist: true}))
+}
@@ -0,0 +1,116 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/grysj/remitly-api/country"
+	"github.com/redis/go-redis/v9"
+)
+
+// townIndexKey is a lex-ordered sorted set per country with one
+// "<TOWN>\x00<SWIFT>" member per bank, so the towns of a country are one
+// ZRANGE and the banks of a town one ZRANGEBYLEX.
+const townIndexKey = "idx:town"
+
+type TownCount struct {
+	Name  string `json:"townName"`
+	Count int    `json:"swiftCodesCount"`
+}
+
+// townKey is the form a town is indexed and matched by: upper case, without
+// accents or punctuation, so "Kraków", "KRAKOW" and "krakow " are one town.
+func townKey(town string) string {
+	folded := strings.Map(func(r rune) rune {
+		if r == '\'' || r == '’' {
+			return -1
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return ' '
+	}, country.FoldAccents(town))
+	return strings.Join(strings.Fields(folded), " ")
+}
+
+// GetTownsByISO2 lists the towns of a country with the number of codes in
+// each, in alphabetical order. A town is shown as written in the first of
+// its records.
+func (s *RedisStore) GetTownsByISO2(iso2 string) ([]TownCount, error) {
+	ctx := context.Background()
+	ks, err := s.active(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.client.ZRange(ctx, ks.townIndex(strings.ToUpper(iso2)), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get towns for ISO2 %s: %w", iso2, err)
+	}
+
+	var towns []TownCount
+	var firstSwifts []string
+	previous := ""
+	for _, member := range members {
+		town, swift, ok := strings.Cut(member, lexSeparator)
+		if !ok {
+			continue
+		}
+		if len(towns) > 0 && town == previous {
+			towns[len(towns)-1].Count++
+			continue
+		}
+		previous = town
+		towns = append(towns, TownCount{Name: town, Count: 1})
+		firstSwifts = append(firstSwifts, swift)
+	}
+	if len(towns) == 0 {
+		return []TownCount{}, nil
+	}
+
+	pipe := s.client.Pipeline()
+	names := make([]*redis.StringCmd, len(firstSwifts))
+	for i, swift := range firstSwifts {
+		names[i] = pipe.HGet(ctx, ks.bank(swift), "town")
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to get town names: %w", err)
+	}
+	for i, name := range names {
+		if written := strings.TrimSpace(name.Val()); written != "" {
+			towns[i].Name = written
+		}
+	}
+	return towns, nil
+}
+
+// GetBanksByTown returns the banks of one town of a country. The town is
+// matched ignoring case, accents and punctuation.
+func (s *RedisStore) GetBanksByTown(iso2, town string) ([]GetBankByIsoResult, error) {
+	key := townKey(town)
+	if key == "" {
+		return []GetBankByIsoResult{}, nil
+	}
+
+	ctx := context.Background()
+	ks, err := s.active(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.client.ZRangeByLex(ctx, ks.townIndex(strings.ToUpper(iso2)), &redis.ZRangeBy{
+		Min: "[" + key + lexSeparator,
+		Max: "[" + key + lexSeparator + "\xff",
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get banks in %s, %s: %w", town, iso2, err)
+	}
+
+	bankKeys := make([]string, 0, len(members))
+	for _, member := range members {
+		bankKeys = append(bankKeys, ks.bank(strings.TrimPrefix(member, key+lexSeparator)))
+	}
+	return s.banksFromKeys(ctx, bankKeys)
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/grysj/remitly-api/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTownIndex(t *testing.T) {
+	require.NoError(t, testStore.client.FlushDB(testCtx).Err())
+	_, err := testStore.ReloadDataset(ReloadDatasetParams{Rows: []parser.CsvRow{
+		{ISO2: "PL", Swift: "BPKOPLPWXXX", Name: "PKO BANK POLSKI S.A.", Town: "WARSZAWA", Country: "POLAND"},
+		{ISO2: "PL", Swift: "ALBPPLP1BMW", Name: "ALIOR BANK S.A.", Town: "Warszawa", Country: "POLAND"},
+		{ISO2: "PL", Swift: "CITIPLPXKRK", Name: "BANK HANDLOWY W WARSZAWIE S.A.", Town: "Kraków", Country: "POLAND"},
+		{ISO2: "PL", Swift: "PKOPPLPWKRK", Name: "BANK PEKAO S.A.", Town: "KRAKOW", Country: "POLAND"},
+		{ISO2: "PL", Swift: "BREXPLPWLOD", Name: "MBANK S.A.", Town: "ŁÓDŹ", Country: "POLAND"},
+		{ISO2: "PL", Swift: "BREXPLPWXXX", Name: "MBANK S.A.", Country: "POLAND"},
+		{ISO2: "MT", Swift: "AKBKMTMTXXX", Name: "AKBANK T.A.S.", Town: "ST. JULIAN'S", Country: "MALTA"},
+	}})
+	require.NoError(t, err)
+
+	towns, err := testStore.GetTownsByISO2("pl")
+	require.NoError(t, err)
+	assert.Equal(t, []TownCount{
+		{Name: "Kraków", Count: 2},
+		{Name: "ŁÓDŹ", Count: 1},
+		{Name: "Warszawa", Count: 2},
+	}, towns)
+
+	towns, err = testStore.GetTownsByISO2("DE")
+	require.NoError(t, err)
+	assert.Empty(t, towns)
+
+	tests := []struct {
+		name string
+		iso2 string
+		town string
+		want []string
+	}{
+		{name: "Case Insensitive", iso2: "PL", town: "warszawa", want: []string{"ALBPPLP1BMW", "BPKOPLPWXXX"}},
+		{name: "Diacritic Insensitive", iso2: "pl", town: "krakow", want: []string{"CITIPLPXKRK", "PKOPPLPWKRK"}},
+		{name: "Diacritics In Query", iso2: "PL", town: "Łódź", want: []string{"BREXPLPWLOD"}},
+		{name: "Punctuation Ignored", iso2: "MT", town: "st julians", want: []string{"AKBKMTMTXXX"}},
+		{name: "Other Country", iso2: "MT", town: "warszawa", want: []string{}},
+		{name: "Town Prefix Does Not Match", iso2: "PL", town: "krak", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			banks, err := testStore.GetBanksByTown(tt.iso2, tt.town)
+			require.NoError(t, err)
+			swifts := make([]string, 0, len(banks))
+			for _, bank := range banks {
+				swifts = append(swifts, bank.Swift)
+			}
+			sort.Strings(swifts)
+			assert.Equal(t, tt.want, swifts)
+		})
+	}
+
+	t.Run("Writes Keep The Index Current", func(t *testing.T) {
+		_, err := testStore.UpdateBank(UpdateBankParams{Bank: Bank{Swift: "PKOPPLPWKRK", ISO2: "PL", Name: "BANK PEKAO S.A.", Town: "GDANSK"}})
+		require.NoError(t, err)
+		require.NoError(t, testStore.DeleteBankFromDB(DeleteBankParams{Swift: "BREXPLPWLOD"}))
+
+		towns, err := testStore.GetTownsByISO2("PL")
+		require.NoError(t, err)
+		assert.Equal(t, []TownCount{
+			{Name: "GDANSK", Count: 1},
+			{Name: "Kraków", Count: 1},
+			{Name: "Warszawa", Count: 2},
+		}, towns)
+
+		report, err := testStore.CheckConsistency(CheckConsistencyParams{})
+		require.NoError(t, err)
+		for _, issue := range report.Issues {
+			assert.Equal(t, IssueOrphanBranchSet, issue.Kind)
+		}
+	})
+	require.NoError(t, testStore.client.FlushDB(testCtx).Err())
+}
@@ -0,0 +1,56 @@
+package iban
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/grysj/remitly-api/bic"
+)
+
+// BankCodes maps a country code and national bank identifier to the BIC
+// of the bank, as loaded from a file like
+// {"PL": {"10901014": "WBKPPLPPXXX"}}.
+type BankCodes map[string]map[string]string
+
+func LoadBankCodes(path string) (BankCodes, error) {
+	codes := make(BankCodes)
+	if path == "" {
+		return codes, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bank code file: %w", err)
+	}
+
+	var raw map[string]map[string]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid bank code file %s: %w", path, err)
+	}
+
+	for country, banks := range raw {
+		country = strings.ToUpper(strings.TrimSpace(country))
+		if _, ok := countrySpecs[country]; !ok {
+			return nil, fmt.Errorf("bank code file %s, %q: %w", path, country, ErrUnknownCountry)
+		}
+		if codes[country] == nil {
+			codes[country] = make(map[string]string)
+		}
+		for bankCode, swift := range banks {
+			code, err := bic.Parse(swift)
+			if err != nil {
+				return nil, fmt.Errorf("bank code file %s, %s %s: %w", path, country, bankCode, err)
+			}
+			codes[country][strings.ToUpper(strings.TrimSpace(bankCode))] = code.BIC11()
+		}
+	}
+	return codes, nil
+}
+
+// Lookup returns the 11-character BIC registered for the IBAN's bank.
+func (c BankCodes) Lookup(i IBAN) (string, bool) {
+	swift, ok := c[i.Country][i.BankCode]
+	return swift, ok
+}
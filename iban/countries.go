@@ -0,0 +1,82 @@
+package iban
+
+// countrySpec describes the IBAN layout of a country: the total length
+// and where the national bank identifier sits within the BBAN.
+type countrySpec struct {
+	length     int
+	bankOffset int
+	bankLength int
+}
+
+// taken from the SWIFT IBAN registry
+var countrySpecs = map[string]countrySpec{
+	"AD": {length: 24, bankLength: 4},
+	"AE": {length: 23, bankLength: 3},
+	"AL": {length: 28, bankLength: 8},
+	"AT": {length: 20, bankLength: 5},
+	"AZ": {length: 28, bankLength: 4},
+	"BA": {length: 20, bankLength: 3},
+	"BE": {length: 16, bankLength: 3},
+	"BG": {length: 22, bankLength: 4},
+	"BH": {length: 22, bankLength: 4},
+	"BR": {length: 29, bankLength: 8},
+	"CH": {length: 21, bankLength: 5},
+	"CY": {length: 28, bankLength: 3},
+	"CZ": {length: 24, bankLength: 4},
+	"DE": {length: 22, bankLength: 8},
+	"DK": {length: 18, bankLength: 4},
+	"DO": {length: 28, bankLength: 4},
+	"EE": {length: 20, bankLength: 2},
+	"EG": {length: 29, bankLength: 4},
+	"ES": {length: 24, bankLength: 4},
+	"FI": {length: 18, bankLength: 3},
+	"FO": {length: 18, bankLength: 4},
+	"FR": {length: 27, bankLength: 5},
+	"GB": {length: 22, bankLength: 4},
+	"GE": {length: 22, bankLength: 2},
+	"GI": {length: 23, bankLength: 4},
+	"GL": {length: 18, bankLength: 4},
+	"GR": {length: 27, bankLength: 3},
+	"GT": {length: 28, bankLength: 4},
+	"HR": {length: 21, bankLength: 7},
+	"HU": {length: 28, bankLength: 3},
+	"IE": {length: 22, bankLength: 4},
+	"IL": {length: 23, bankLength: 3},
+	"IS": {length: 26, bankLength: 4},
+	"IT": {length: 27, bankOffset: 1, bankLength: 5},
+	"JO": {length: 30, bankLength: 4},
+	"KW": {length: 30, bankLength: 4},
+	"KZ": {length: 20, bankLength: 3},
+	"LB": {length: 28, bankLength: 4},
+	"LI": {length: 21, bankLength: 5},
+	"LT": {length: 20, bankLength: 5},
+	"LU": {length: 20, bankLength: 3},
+	"LV": {length: 21, bankLength: 4},
+	"MC": {length: 27, bankLength: 5},
+	"MD": {length: 24, bankLength: 2},
+	"ME": {length: 22, bankLength: 3},
+	"MK": {length: 19, bankLength: 3},
+	"MR": {length: 27, bankLength: 5},
+	"MT": {length: 31, bankLength: 4},
+	"MU": {length: 30, bankLength: 6},
+	"NL": {length: 18, bankLength: 4},
+	"NO": {length: 15, bankLength: 4},
+	"PK": {length: 24, bankLength: 4},
+	"PL": {length: 28, bankLength: 8},
+	"PS": {length: 29, bankLength: 4},
+	"PT": {length: 25, bankLength: 4},
+	"QA": {length: 29, bankLength: 4},
+	"RO": {length: 24, bankLength: 4},
+	"RS": {length: 22, bankLength: 3},
+	"SA": {length: 24, bankLength: 2},
+	"SC": {length: 31, bankLength: 6},
+	"SE": {length: 24, bankLength: 3},
+	"SI": {length: 19, bankLength: 5},
+	"SK": {length: 24, bankLength: 4},
+	"SM": {length: 27, bankOffset: 1, bankLength: 5},
+	"TN": {length: 24, bankLength: 2},
+	"TR": {length: 26, bankLength: 5},
+	"UA": {length: 29, bankLength: 6},
+	"VG": {length: 24, bankLength: 4},
+	"XK": {length: 20, bankLength: 2},
+}
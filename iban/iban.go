@@ -0,0 +1,91 @@
+package iban
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidCharacters = errors.New("IBAN contains invalid characters")
+	ErrUnknownCountry    = errors.New("IBAN country is not supported")
+	ErrInvalidLength     = errors.New("IBAN has the wrong length for its country")
+	ErrInvalidChecksum   = errors.New("IBAN check digits do not match")
+)
+
+type IBAN struct {
+	Country     string
+	CheckDigits string
+	BBAN        string
+	BankCode    string
+}
+
+// Parse validates an IBAN in electronic or print format: spaces are
+// ignored and letters are upper-cased.
+func Parse(value string) (IBAN, error) {
+	code := strings.ToUpper(strings.Join(strings.Fields(value), ""))
+	if len(code) < 5 {
+		return IBAN{}, fmt.Errorf("%q: %w", code, ErrInvalidLength)
+	}
+	for i := 0; i < len(code); i++ {
+		c := code[i]
+		isLetter := c >= 'A' && c <= 'Z'
+		isDigit := c >= '0' && c <= '9'
+		if (i < 2 && !isLetter) || (i >= 2 && i < 4 && !isDigit) || (!isLetter && !isDigit) {
+			return IBAN{}, fmt.Errorf("%q: %w", code, ErrInvalidCharacters)
+		}
+	}
+
+	country := code[:2]
+	spec, ok := countrySpecs[country]
+	if !ok {
+		return IBAN{}, fmt.Errorf("%q: %w", country, ErrUnknownCountry)
+	}
+	if len(code) != spec.length {
+		return IBAN{}, fmt.Errorf("%q has %d characters, expected %d: %w", code, len(code), spec.length, ErrInvalidLength)
+	}
+	if mod97(code[4:]+code[:4]) != 1 {
+		return IBAN{}, fmt.Errorf("%q: %w", code, ErrInvalidChecksum)
+	}
+
+	bban := code[4:]
+	return IBAN{
+		Country:     country,
+		CheckDigits: code[2:4],
+		BBAN:        bban,
+		BankCode:    bban[spec.bankOffset : spec.bankOffset+spec.bankLength],
+	}, nil
+}
+
+func (i IBAN) String() string {
+	return i.Country + i.CheckDigits + i.BBAN
+}
+
+// PrintFormat groups the IBAN in blocks of four characters.
+func (i IBAN) PrintFormat() string {
+	code := i.String()
+	var b strings.Builder
+	for pos := 0; pos < len(code); pos += 4 {
+		if pos > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(code[pos:min(pos+4, len(code))])
+	}
+	return b.String()
+}
+
+// mod97 computes the ISO 7064 remainder, expanding letters to 10..35
+// digit by digit so the number never overflows.
+func mod97(code string) int {
+	remainder := 0
+	for i := 0; i < len(code); i++ {
+		c := code[i]
+		if c >= 'A' && c <= 'Z' {
+			value := int(c-'A') + 10
+			remainder = (remainder*100 + value) % 97
+		} else {
+			remainder = (remainder*10 + int(c-'0')) % 97
+		}
+	}
+	return remainder
+}
@@ -0,0 +1,84 @@
+package iban
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		wantCountry  string
+		wantBankCode string
+		wantErr      error
+	}{
+		{name: "print format", input: "PL61 1090 1014 0000 0712 1981 2874", wantCountry: "PL", wantBankCode: "10901014"},
+		{name: "electronic format", input: "DE89370400440532013000", wantCountry: "DE", wantBankCode: "37040044"},
+		{name: "lower case letters", input: "gb82 west 1234 5698 7654 32", wantCountry: "GB", wantBankCode: "WEST"},
+		{name: "bank code after check character", input: "IT60 X054 2811 1010 0000 0123 456", wantCountry: "IT", wantBankCode: "05428"},
+		{name: "alphanumeric BBAN", input: "MT84 MALT 0110 0001 2345 MTLC AST0 01S", wantCountry: "MT", wantBankCode: "MALT"},
+		{name: "shortest country", input: "NO93 8601 1117 947", wantCountry: "NO", wantBankCode: "8601"},
+		{name: "wrong checksum", input: "PL62 1090 1014 0000 0712 1981 2874", wantErr: ErrInvalidChecksum},
+		{name: "wrong length", input: "PL61 1090 1014 0000 0712 1981 287", wantErr: ErrInvalidLength},
+		{name: "unsupported country", input: "US12 3456 7890", wantErr: ErrUnknownCountry},
+		{name: "punctuation", input: "PL61-1090-1014", wantErr: ErrInvalidCharacters},
+		{name: "letters in check digits", input: "PLAB10901014000007121981287", wantErr: ErrInvalidCharacters},
+		{name: "empty", input: "", wantErr: ErrInvalidLength},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCountry, got.Country)
+			assert.Equal(t, tt.wantBankCode, got.BankCode)
+		})
+	}
+}
+
+func TestPrintFormat(t *testing.T) {
+	code, err := Parse("NO9386011117947")
+	require.NoError(t, err)
+	assert.Equal(t, "NO9386011117947", code.String())
+	assert.Equal(t, "NO93 8601 1117 947", code.PrintFormat())
+}
+
+func TestLoadBankCodes(t *testing.T) {
+	tmpDir := t.TempDir()
+	write := func(content string) string {
+		path := filepath.Join(tmpDir, "bank_codes.json")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0666))
+		return path
+	}
+
+	codes, err := LoadBankCodes(write(`{"pl": {"10901014": "wbkpplpp"}}`))
+	require.NoError(t, err)
+	code, err := Parse("PL61 1090 1014 0000 0712 1981 2874")
+	require.NoError(t, err)
+	swift, ok := codes.Lookup(code)
+	require.True(t, ok)
+	assert.Equal(t, "WBKPPLPPXXX", swift)
+
+	_, err = LoadBankCodes(write(`{"PL": {"10901014": "NOTABIC"}}`))
+	require.Error(t, err)
+
+	_, err = LoadBankCodes(write(`{"US": {"1234": "WBKPPLPPXXX"}}`))
+	require.ErrorIs(t, err, ErrUnknownCountry)
+
+	_, err = LoadBankCodes(filepath.Join(tmpDir, "missing.json"))
+	require.Error(t, err)
+
+	codes, err = LoadBankCodes("")
+	require.NoError(t, err)
+	_, ok = codes.Lookup(code)
+	assert.False(t, ok)
+}
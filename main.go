@@ -1,7 +1,11 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
+	"flag"
 	"log"
+	"os"
 
 	"github.com/grysj/remitly-api/api"
 	"github.com/grysj/remitly-api/config"
@@ -9,28 +13,105 @@ import (
 	"github.com/grysj/remitly-api/parser"
 )
 
+const maxLoggedRowErrors = 20
+
+// maxDeltaAttempts bounds how often -delta diffs again after the store was
+// changed through the API between the diff and the apply.
+const maxDeltaAttempts = 3
+
+type dryRunReport struct {
+	Files      []parser.FileSummary    `json:"files"`
+	RowErrors  []parser.RowError       `json:"rowErrors"`
+	Validation parser.ValidationReport `json:"validation"`
+	Delta      *db.DatasetDelta        `json:"delta,omitempty"`
+}
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "fsck" {
+		os.Exit(runFsck(os.Args[2:]))
+	}
+
+	dryRun := flag.Bool("dry-run", false, "parse and validate the dataset, print the report and exit")
+	delta := flag.Bool("delta", false, "diff the dataset against the store and apply only the differences, removing codes missing from the file")
+	flag.Parse()
+
 	cfg := config.LoadConfig()
 
-	store, err := db.NewRedisStore(db.NewRedisStoreParams{
-		RedisDB:       0,
-		RedisHost:     cfg.RedisHost,
-		RedisPort:     cfg.RedisPort,
-		RedisPassword: cfg.RedisPassword,
-	})
+	params, err := cfg.ImportParams()
 	if err != nil {
-		log.Fatalf("Could not connect to Redis: %v", err)
+		log.Fatalf("invalid import configuration: %v", err)
 	}
 
-	parsed, err := parser.ParseCSV(cfg.CsvPath)
-	if err != nil {
+	parsed, err := parser.ParseSource(cfg.CsvPath, params)
+	if parsed != nil {
+		logParseSummary(cfg.CsvPath, parsed)
+	}
+	if *dryRun && parsed == nil {
+		log.Printf("cannot parse file: %v", err)
+		os.Exit(2)
+	}
+	if err != nil && !*dryRun {
 		log.Fatalf("cannot parse file: %v", err)
 	}
+	parseFailed := err != nil
+
+	report := dryRunReport{
+		Files:      parsed.Files,
+		RowErrors:  append(make([]parser.RowError, 0), parsed.Errors...),
+		Validation: parser.Validate(parsed.Rows),
+	}
+	logValidationSummary(report.Validation)
+
+	if *dryRun && !*delta {
+		os.Exit(finishDryRun(report, parseFailed))
+	}
 
-	if err := store.AddBanksFromCSV(parsed); err != nil {
-		log.Fatalf("cannot init db: %v", err)
+	store, err := openStore(cfg)
+	if err != nil {
+		log.Fatalf("Could not connect to Redis: %v", err)
 	}
 
+	if *delta {
+		diff, err := store.DiffBanks(parsed.Rows)
+		if err != nil {
+			log.Fatalf("cannot diff dataset: %v", err)
+		}
+		log.Printf("delta: %d added, %d removed, %d changed, %d unchanged",
+			len(diff.Added), len(diff.Removed), len(diff.Changed), diff.Unchanged)
+
+		if *dryRun {
+			report.Delta = diff
+			os.Exit(finishDryRun(report, parseFailed))
+		}
+		err = store.ApplyDelta(diff)
+		for attempt := 1; errors.Is(err, db.ErrDeltaOutdated) && attempt < maxDeltaAttempts; attempt++ {
+			log.Printf("%v, diffing again", err)
+			if diff, err = store.DiffBanks(parsed.Rows); err != nil {
+				log.Fatalf("cannot diff dataset: %v", err)
+			}
+			err = store.ApplyDelta(diff)
+		}
+		if err != nil {
+			log.Fatalf("cannot apply delta: %v", err)
+		}
+	} else {
+		dataset, err := store.ReloadDataset(db.ReloadDatasetParams{
+			Rows:   parsed.Rows,
+			Source: cfg.CsvPath,
+		})
+		if err != nil && dataset == nil {
+			log.Fatalf("cannot init db: %v", err)
+		}
+		if err != nil {
+			log.Printf("warning: %v", err)
+		}
+		log.Printf("dataset version %s is active (%d rows, %s)", dataset.ID, dataset.Rows, dataset.Checksum)
+	}
+
+	serve(store, cfg)
+}
+
+func serve(store *db.Store, cfg *config.Config) {
 	server, err := api.NewServer(store, *cfg)
 	if err != nil {
 		log.Fatalf("cannot configure server: %v", err)
@@ -40,3 +121,91 @@ func main() {
 		log.Fatalf("cannot start server: %v", err)
 	}
 }
+
+func openStore(cfg *config.Config) (*db.Store, error) {
+	return db.NewRedisStore(db.NewRedisStoreParams{
+		RedisDB:       0,
+		RedisHost:     cfg.RedisHost,
+		RedisPort:     cfg.RedisPort,
+		RedisPassword: cfg.RedisPassword,
+
+		DatasetRetention: cfg.DatasetRetention,
+		DeletedRetention: cfg.DeletedRetention,
+	})
+}
+
+// runFsck checks the indexes of the active dataset version, and rebuilds
+// them with -repair. It exits with 1 when issues were found and left as
+// they are.
+func runFsck(args []string) int {
+	flags := flag.NewFlagSet("fsck", flag.ExitOnError)
+	repair := flags.Bool("repair", false, "rebuild every secondary index from the bank records")
+	flags.Parse(args)
+
+	store, err := openStore(config.LoadConfig())
+	if err != nil {
+		log.Printf("Could not connect to Redis: %v", err)
+		return 2
+	}
+	defer store.CloseConnection()
+
+	report, err := store.CheckConsistency(db.CheckConsistencyParams{Repair: *repair})
+	if err != nil {
+		log.Printf("cannot check store: %v", err)
+		return 2
+	}
+	log.Printf("fsck: %d banks, %d issues, repaired=%t", report.Banks, len(report.Issues), report.Repaired)
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Printf("cannot encode report: %v", err)
+		return 2
+	}
+
+	if len(report.Issues) > 0 && !report.Repaired {
+		return 1
+	}
+	return 0
+}
+
+func finishDryRun(report dryRunReport, parseFailed bool) int {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Printf("cannot encode report: %v", err)
+		return 2
+	}
+
+	if parseFailed || !report.Validation.Valid {
+		return 1
+	}
+	return 0
+}
+
+func logParseSummary(path string, result *parser.ParseResult) {
+	log.Printf("parsed %s: %d rows accepted, %d rows rejected", path, len(result.Rows), len(result.Errors))
+	if len(result.Files) > 1 {
+		for _, file := range result.Files {
+			log.Printf("  %s: %d rows, %d rejected, %d duplicates", file.File, file.Rows, file.Rejected, file.Duplicates)
+		}
+	}
+	for i, rowErr := range result.Errors {
+		if i == maxLoggedRowErrors {
+			log.Printf("... %d more row errors", len(result.Errors)-maxLoggedRowErrors)
+			break
+		}
+		log.Printf("  %v", rowErr)
+	}
+}
+
+func logValidationSummary(report parser.ValidationReport) {
+	if len(report.Issues) == 0 {
+		log.Printf("validation: %d rows, no issues", report.Rows)
+		return
+	}
+	log.Printf("validation: %d rows, %d issues, valid=%t", report.Rows, len(report.Issues), report.Valid)
+	for kind, count := range report.CountByKind() {
+		log.Printf("  %s: %d", kind, count)
+	}
+}
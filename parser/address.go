@@ -0,0 +1,96 @@
+package parser
+
+import (
+	"strings"
+	"unicode"
+)
+
+const maxPostcodeLen = 10
+
+// Address is the structured form of an ADDRESS column. Source files
+// typically use "<street> <TOWN>, <REGION>, <POSTCODE>" where the street
+// itself may contain commas.
+type Address struct {
+	StreetLines []string `json:"streetLines"`
+	Postcode    string   `json:"postcode,omitempty"`
+	Town        string   `json:"townName,omitempty"`
+	Region      string   `json:"region,omitempty"`
+}
+
+func ParseAddress(raw, town string) Address {
+	town = collapseSpaces(town)
+	address := Address{
+		StreetLines: make([]string, 0),
+		Town:        town,
+	}
+
+	var parts []string
+	for _, part := range strings.Split(raw, ",") {
+		if part = collapseSpaces(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	if len(parts) > 1 && looksLikePostcode(parts[len(parts)-1]) {
+		address.Postcode = parts[len(parts)-1]
+		parts = parts[:len(parts)-1]
+	}
+
+	anchor := -1
+	if town != "" {
+		for i, part := range parts {
+			if strings.EqualFold(part, town) || hasSuffixFold(part, " "+town) {
+				anchor = i
+				break
+			}
+		}
+	}
+
+	switch {
+	case anchor >= 0:
+		address.StreetLines = append(address.StreetLines, parts[:anchor]...)
+		if !strings.EqualFold(parts[anchor], town) {
+			street := strings.TrimRight(parts[anchor][:len(parts[anchor])-len(town)], " -,")
+			if street != "" {
+				address.StreetLines = append(address.StreetLines, street)
+			}
+		}
+		address.Region = strings.Join(parts[anchor+1:], ", ")
+	case address.Postcode != "" && len(parts) > 1:
+		address.StreetLines = append(address.StreetLines, parts[:len(parts)-1]...)
+		address.Region = parts[len(parts)-1]
+	default:
+		address.StreetLines = append(address.StreetLines, parts...)
+	}
+
+	if strings.EqualFold(address.Region, town) {
+		address.Region = ""
+	}
+
+	return address
+}
+
+func looksLikePostcode(s string) bool {
+	if len(s) > maxPostcodeLen || len(strings.Fields(s)) > 2 {
+		return false
+	}
+	hasDigit := false
+	for _, c := range s {
+		switch {
+		case unicode.IsDigit(c):
+			hasDigit = true
+		case unicode.IsLetter(c), c == ' ', c == '-':
+		default:
+			return false
+		}
+	}
+	return hasDigit
+}
+
+func hasSuffixFold(s, suffix string) bool {
+	return len(s) >= len(suffix) && strings.EqualFold(s[len(s)-len(suffix):], suffix)
+}
+
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
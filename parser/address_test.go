@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		town     string
+		expected Address
+	}{
+		{
+			name: "street repeats town",
+			raw:  "HYRJA 3 RR. DRITAN HOXHA ND. 11 TIRANA, TIRANA, 1023",
+			town: "TIRANA",
+			expected: Address{
+				StreetLines: []string{"HYRJA 3 RR. DRITAN HOXHA ND. 11"},
+				Postcode:    "1023",
+				Town:        "TIRANA",
+			},
+		},
+		{
+			name: "region and dashed postcode",
+			raw:  "UL CHLODNA 52  WARSZAWA, MAZOWIECKIE, 00-872",
+			town: "WARSZAWA",
+			expected: Address{
+				StreetLines: []string{"UL CHLODNA 52"},
+				Postcode:    "00-872",
+				Town:        "WARSZAWA",
+				Region:      "MAZOWIECKIE",
+			},
+		},
+		{
+			name: "street with commas",
+			raw:  "CERRO COLORADO 5240, FLOOR 8 TORRE 2 - SANTIAGO, PROVINCIA DE SANTIAGO, 8320000",
+			town: "SANTIAGO",
+			expected: Address{
+				StreetLines: []string{"CERRO COLORADO 5240", "FLOOR 8 TORRE 2"},
+				Postcode:    "8320000",
+				Town:        "SANTIAGO",
+				Region:      "PROVINCIA DE SANTIAGO",
+			},
+		},
+		{
+			name: "postcode with letters",
+			raw:  "FLOOR 3, TOWER BUSINESS CENTRE TOWER STREET BIRKIRKARA, BIRKIRKARA, BKR 4013",
+			town: "BIRKIRKARA",
+			expected: Address{
+				StreetLines: []string{"FLOOR 3", "TOWER BUSINESS CENTRE TOWER STREET"},
+				Postcode:    "BKR 4013",
+				Town:        "BIRKIRKARA",
+			},
+		},
+		{
+			name: "town only",
+			raw:  "  OBORNIKI , WIELKOPOLSKIE, 64-600",
+			town: "OBORNIKI",
+			expected: Address{
+				StreetLines: []string{},
+				Postcode:    "64-600",
+				Town:        "OBORNIKI",
+				Region:      "WIELKOPOLSKIE",
+			},
+		},
+		{
+			name: "town not in address",
+			raw:  "PORTOMASO BUSINESS TOWER",
+			town: "ST. JULIAN'S",
+			expected: Address{
+				StreetLines: []string{"PORTOMASO BUSINESS TOWER"},
+				Town:        "ST. JULIAN'S",
+			},
+		},
+		{
+			name: "empty address",
+			raw:  "  ",
+			town: "SANTIAGO",
+			expected: Address{
+				StreetLines: []string{},
+				Town:        "SANTIAGO",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, ParseAddress(tt.raw, tt.town))
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package parser
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zipMagic  = []byte("PK\x03\x04")
+)
+
+func isGzipName(name string) bool {
+	return strings.EqualFold(path.Ext(name), ".gz")
+}
+
+func isZip(name string, r io.ReaderAt) bool {
+	if strings.EqualFold(path.Ext(name), ".zip") {
+		return true
+	}
+	magic := make([]byte, len(zipMagic))
+	n, _ := r.ReadAt(magic, 0)
+	return bytes.Equal(magic[:n], zipMagic)
+}
+
+// IsSupportedFile reports whether a file name has an extension the
+// importer understands, including compressed variants.
+func IsSupportedFile(name string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	if ext == ".gz" {
+		ext = strings.ToLower(path.Ext(strings.TrimSuffix(name, path.Ext(name))))
+	}
+	_, ok := extensionFormats[ext]
+	return ok || ext == ".zip"
+}
+
+func parseGzip(r io.Reader, name string, params ParseParams) (*ParseResult, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gzip data: %w", err)
+	}
+	defer gz.Close()
+
+	inner := gz.Name
+	if isGzipName(name) {
+		inner = strings.TrimSuffix(name, path.Ext(name))
+	}
+	return ParseReader(gz, inner, params)
+}
+
+// ParseZip imports every supported file in the archive. Entries are
+// decompressed one at a time while they are parsed, and the strict mode
+// check is applied to the combined result.
+func ParseZip(r io.ReaderAt, size int64, params ParseParams) (*ParseResult, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid zip archive: %w", err)
+	}
+
+	entryParams := params
+	entryParams.Mode = Lenient
+
+	merged := &ParseResult{}
+	imported := 0
+	for _, entry := range archive.File {
+		if entry.FileInfo().IsDir() || strings.HasPrefix(entry.Name, "__MACOSX/") {
+			continue
+		}
+		if !IsSupportedFile(entry.Name) || strings.EqualFold(path.Ext(entry.Name), ".zip") {
+			continue
+		}
+
+		result, err := parseZipEntry(entry, entryParams)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name, err)
+		}
+		merged.merge(result, entry.Name)
+		imported++
+	}
+
+	if imported == 0 {
+		return nil, fmt.Errorf("zip archive contains no supported files")
+	}
+
+	return finishResult(merged, params)
+}
+
+func parseZipEntry(entry *zip.File, params ParseParams) (*ParseResult, error) {
+	rc, err := entry.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return ParseReader(rc, entry.Name, params)
+}
+
+func (result *ParseResult) merge(other *ParseResult, source string) {
+	for _, row := range other.Rows {
+		row.File = joinSource(source, row.File)
+		result.Rows = append(result.Rows, row)
+	}
+	for _, rowErr := range other.Errors {
+		rowErr.File = joinSource(source, rowErr.File)
+		result.Errors = append(result.Errors, rowErr)
+	}
+}
+
+func joinSource(outer, inner string) string {
+	if inner == "" {
+		return outer
+	}
+	return outer + "!" + inner
+}
@@ -0,0 +1,101 @@
+package parser
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCompressedFiles(t *testing.T) {
+	csvData := testHeader + "AL,AAISALTRXXX,BIC11,UNITED BANK OF ALBANIA SH.A,,TIRANA,ALBANIA,Europe/Tirane\n"
+	jsonData := `[{"swiftCode": "ABIEBGS1XXX", "bankName": "ABV INVESTMENTS LTD", "countryISO2": "BG"}]`
+	brokenCSV := testHeader + "BG,,BIC11,NO SWIFT,,VARNA,BULGARIA,Europe/Sofia\n"
+
+	tmpDir := t.TempDir()
+
+	var gzData bytes.Buffer
+	gz := gzip.NewWriter(&gzData)
+	_, err := gz.Write([]byte(csvData))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	gzPath := filepath.Join(tmpDir, "SWIFT_CODES.csv.gz")
+	require.NoError(t, os.WriteFile(gzPath, gzData.Bytes(), 0666))
+
+	writeZip := func(name string, entries map[string]string) string {
+		var buf bytes.Buffer
+		archive := zip.NewWriter(&buf)
+		for entryName, content := range entries {
+			w, err := archive.Create(entryName)
+			require.NoError(t, err)
+			_, err = w.Write([]byte(content))
+			require.NoError(t, err)
+		}
+		require.NoError(t, archive.Close())
+		zipPath := filepath.Join(tmpDir, name)
+		require.NoError(t, os.WriteFile(zipPath, buf.Bytes(), 0666))
+		return zipPath
+	}
+
+	t.Run("gzip file", func(t *testing.T) {
+		result, err := ParseFile(gzPath, ParseParams{})
+		require.NoError(t, err)
+		require.Len(t, result.Rows, 1)
+		require.Equal(t, "AAISALTRXXX", result.Rows[0].Swift)
+	})
+
+	t.Run("gzip file through ParseCSV", func(t *testing.T) {
+		rows, err := ParseCSV(gzPath)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, "AAISALTRXXX", rows[0].Swift)
+	})
+
+	t.Run("gzip stream without name", func(t *testing.T) {
+		result, err := ParseReader(bytes.NewReader(gzData.Bytes()), "", ParseParams{})
+		require.NoError(t, err)
+		require.Len(t, result.Rows, 1)
+	})
+
+	t.Run("zip archive with several formats", func(t *testing.T) {
+		zipPath := writeZip("directory.zip", map[string]string{
+			"al/codes.csv":  csvData,
+			"bg/codes.json": jsonData,
+			"README.txt":    "not a dataset",
+		})
+
+		result, err := ParseFile(zipPath, ParseParams{})
+		require.NoError(t, err)
+		require.Len(t, result.Rows, 2)
+
+		files := map[string]string{}
+		for _, row := range result.Rows {
+			files[row.Swift] = row.File
+		}
+		require.Equal(t, map[string]string{"AAISALTRXXX": "al/codes.csv", "ABIEBGS1XXX": "bg/codes.json"}, files)
+	})
+
+	t.Run("zip archive strict mode", func(t *testing.T) {
+		zipPath := writeZip("broken.zip", map[string]string{
+			"ok.csv":     csvData,
+			"broken.csv": brokenCSV,
+		})
+
+		result, err := ParseFile(zipPath, ParseParams{Mode: Strict})
+		var parseErr *ParseError
+		require.ErrorAs(t, err, &parseErr)
+		require.Len(t, result.Errors, 1)
+		require.Equal(t, "broken.csv", result.Errors[0].File)
+	})
+
+	t.Run("zip archive without datasets", func(t *testing.T) {
+		zipPath := writeZip("empty.zip", map[string]string{"README.txt": "nothing here"})
+
+		_, err := ParseFile(zipPath, ParseParams{})
+		require.Error(t, err)
+	})
+}
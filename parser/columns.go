@@ -0,0 +1,117 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	ColumnISO2     = "COUNTRY ISO2 CODE"
+	ColumnSwift    = "SWIFT CODE"
+	ColumnType     = "CODE TYPE"
+	ColumnName     = "NAME"
+	ColumnAddress  = "ADDRESS"
+	ColumnTown     = "TOWN NAME"
+	ColumnCountry  = "COUNTRY NAME"
+	ColumnTimezone = "TIME ZONE"
+)
+
+var requiredColumns = []string{ColumnISO2, ColumnSwift, ColumnName}
+
+var optionalColumns = []string{ColumnType, ColumnAddress, ColumnTown, ColumnCountry, ColumnTimezone}
+
+var allColumns = append(append([]string{}, requiredColumns...), optionalColumns...)
+
+var builtinAliases = map[string][]string{
+	ColumnISO2:     {"COUNTRY CODE", "COUNTRY ISO2", "COUNTRY ISO CODE", "ISO2", "ISO2 CODE", "COUNTRYISO2", "CTRY"},
+	ColumnSwift:    {"SWIFT", "SWIFTCODE", "SWIFT BIC", "SWIFT/BIC", "BIC", "BIC CODE", "BIC11", "BICFI"},
+	ColumnType:     {"TYPE", "BIC TYPE", "CODETYPE"},
+	ColumnName:     {"BANK NAME", "BANKNAME", "INSTITUTION NAME", "INSTITUTION", "BANK"},
+	ColumnAddress:  {"BANK ADDRESS", "STREET ADDRESS"},
+	ColumnTown:     {"TOWN", "TOWNNAME", "CITY", "CITY NAME"},
+	ColumnCountry:  {"COUNTRY", "COUNTRYNAME"},
+	ColumnTimezone: {"TIMEZONE", "TZ"},
+}
+
+// ColumnMapping maps a vendor header onto one of the Column* names. Keys
+// are compared after normalizeHeader, values are canonical column names.
+type ColumnMapping map[string]string
+
+func LoadColumnMapping(path, spec string) (ColumnMapping, error) {
+	mapping := make(ColumnMapping)
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read column mapping: %w", err)
+		}
+		var fromFile map[string]string
+		if err := json.Unmarshal(data, &fromFile); err != nil {
+			return nil, fmt.Errorf("invalid column mapping file %s: %w", path, err)
+		}
+		for header, column := range fromFile {
+			if err := mapping.add(header, column); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if spec != "" {
+		for _, pair := range strings.Split(spec, ",") {
+			header, column, ok := strings.Cut(pair, "=")
+			if !ok {
+				return nil, fmt.Errorf("invalid column mapping %q: expected HEADER=COLUMN", pair)
+			}
+			if err := mapping.add(header, column); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return mapping, nil
+}
+
+func (m ColumnMapping) add(header, column string) error {
+	canonical := normalizeHeader(column)
+	if !isKnownColumn(canonical) {
+		return fmt.Errorf("invalid column mapping for %q: unknown column %q", header, column)
+	}
+	m[normalizeHeader(header)] = canonical
+	return nil
+}
+
+func isKnownColumn(column string) bool {
+	for _, known := range allColumns {
+		if column == known {
+			return true
+		}
+	}
+	return false
+}
+
+// candidates lists the headers accepted for a column, most specific first:
+// user mapping, the canonical name, then the built-in aliases.
+func (m ColumnMapping) candidates(column string) []string {
+	var names []string
+	for header, target := range m {
+		if target == column {
+			names = append(names, header)
+		}
+	}
+	names = append(names, column)
+	return append(names, builtinAliases[column]...)
+}
+
+func normalizeHeader(header string) string {
+	header = strings.TrimSpace(strings.TrimPrefix(header, "\ufeff"))
+	header = strings.Map(func(r rune) rune {
+		switch r {
+		case '_', '-', '.':
+			return ' '
+		}
+		return r
+	}, header)
+	return strings.ToUpper(strings.Join(strings.Fields(header), " "))
+}
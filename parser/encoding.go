@@ -0,0 +1,252 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+const (
+	EncodingAuto        = "auto"
+	EncodingUTF8        = "utf-8"
+	EncodingUTF16       = "utf-16"
+	EncodingWindows1252 = "windows-1252"
+	EncodingLatin1      = "iso-8859-1"
+)
+
+// ErrMixedEncoding means auto-detected input has valid multi-byte UTF-8
+// followed by bytes that are not UTF-8, so no single encoding reads it.
+var ErrMixedEncoding = errors.New("input mixes UTF-8 with another encoding, set the encoding explicitly")
+
+var encodingAliases = map[string]string{
+	"":             EncodingAuto,
+	"auto":         EncodingAuto,
+	"utf-8":        EncodingUTF8,
+	"utf8":         EncodingUTF8,
+	"utf-16":       EncodingUTF16,
+	"utf16":        EncodingUTF16,
+	"windows-1252": EncodingWindows1252,
+	"cp1252":       EncodingWindows1252,
+	"iso-8859-1":   EncodingLatin1,
+	"iso8859-1":    EncodingLatin1,
+	"latin1":       EncodingLatin1,
+	"latin-1":      EncodingLatin1,
+}
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// windows1252 holds the code points for 0x80-0x9F, the only range where
+// Windows-1252 differs from ISO-8859-1. Undefined bytes map to U+FFFD.
+var windows1252 = [32]rune{
+	'€', utf8.RuneError, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', utf8.RuneError, 'Ž', utf8.RuneError,
+	utf8.RuneError, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', utf8.RuneError, 'ž', 'Ÿ',
+}
+
+func NormalizeEncoding(name string) (string, error) {
+	encoding, ok := encodingAliases[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return "", fmt.Errorf("unsupported encoding %q", name)
+	}
+	return encoding, nil
+}
+
+// decodeInput strips a byte order mark and transcodes the input to UTF-8.
+// A BOM always wins over the configured encoding. In auto mode the whole
+// input is checked as it is read, and input that is not valid UTF-8 is read
+// as Windows-1252, which is what Excel produces for Western European locales.
+func decodeInput(r io.Reader, encoding string) (io.Reader, error) {
+	encoding, err := NormalizeEncoding(encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	buffered := bufio.NewReader(r)
+	head, err := buffered.Peek(len(bomUTF8))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	switch {
+	case bytes.HasPrefix(head, bomUTF8):
+		buffered.Discard(len(bomUTF8))
+		return buffered, nil
+	case bytes.HasPrefix(head, bomUTF16LE):
+		buffered.Discard(len(bomUTF16LE))
+		return &utf16Reader{r: buffered, bigEndian: false}, nil
+	case bytes.HasPrefix(head, bomUTF16BE):
+		buffered.Discard(len(bomUTF16BE))
+		return &utf16Reader{r: buffered, bigEndian: true}, nil
+	}
+
+	switch encoding {
+	case EncodingUTF8:
+		return buffered, nil
+	case EncodingUTF16:
+		return &utf16Reader{r: buffered, bigEndian: false}, nil
+	case EncodingWindows1252:
+		return &singleByteReader{r: buffered, table: &windows1252}, nil
+	case EncodingLatin1:
+		return &singleByteReader{r: buffered}, nil
+	}
+	return &autoReader{r: buffered}, nil
+}
+
+// autoReader passes UTF-8 through until the first invalid byte and reads
+// the rest as Windows-1252. That decodes the file exactly as Windows-1252
+// only while everything before the invalid byte was ASCII, so valid
+// multi-byte UTF-8 followed by an invalid byte fails with ErrMixedEncoding.
+type autoReader struct {
+	r         *bufio.Reader
+	fallback  io.Reader
+	multibyte bool
+	pending   []byte
+}
+
+func (a *autoReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(a.pending) > 0 {
+			copied := copy(p[n:], a.pending)
+			a.pending = a.pending[copied:]
+			n += copied
+			continue
+		}
+
+		if a.fallback != nil {
+			read, err := a.fallback.Read(p[n:])
+			n += read
+			if n > 0 && err == io.EOF {
+				return n, nil
+			}
+			return n, err
+		}
+
+		r, size, err := a.r.ReadRune()
+		if err != nil {
+			if n > 0 && err == io.EOF {
+				return n, nil
+			}
+			return n, err
+		}
+
+		if r == utf8.RuneError && size == 1 {
+			if a.multibyte {
+				return n, ErrMixedEncoding
+			}
+			a.r.UnreadRune()
+			a.fallback = &singleByteReader{r: a.r, table: &windows1252}
+			continue
+		}
+		if size > 1 {
+			a.multibyte = true
+		}
+		a.pending = utf8.AppendRune(a.pending[:0], r)
+	}
+	return n, nil
+}
+
+type singleByteReader struct {
+	r       io.ByteReader
+	table   *[32]rune
+	pending []byte
+}
+
+func (s *singleByteReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(s.pending) > 0 {
+			copied := copy(p[n:], s.pending)
+			s.pending = s.pending[copied:]
+			n += copied
+			continue
+		}
+
+		b, err := s.r.ReadByte()
+		if err != nil {
+			if n > 0 && err == io.EOF {
+				return n, nil
+			}
+			return n, err
+		}
+
+		if b < utf8.RuneSelf {
+			p[n] = b
+			n++
+			continue
+		}
+
+		r := rune(b)
+		if s.table != nil && b >= 0x80 && b <= 0x9F {
+			r = s.table[b-0x80]
+		}
+		s.pending = utf8.AppendRune(s.pending[:0], r)
+	}
+	return n, nil
+}
+
+type utf16Reader struct {
+	r         io.ByteReader
+	bigEndian bool
+	pending   []byte
+}
+
+func (u *utf16Reader) readUnit() (uint16, error) {
+	first, err := u.r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	second, err := u.r.ReadByte()
+	if err == io.EOF {
+		return 0, io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return 0, err
+	}
+	if u.bigEndian {
+		return uint16(first)<<8 | uint16(second), nil
+	}
+	return uint16(second)<<8 | uint16(first), nil
+}
+
+func (u *utf16Reader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(u.pending) > 0 {
+			copied := copy(p[n:], u.pending)
+			u.pending = u.pending[copied:]
+			n += copied
+			continue
+		}
+
+		unit, err := u.readUnit()
+		if err != nil {
+			if n > 0 && err == io.EOF {
+				return n, nil
+			}
+			return n, err
+		}
+
+		r := rune(unit)
+		switch {
+		case r >= 0xD800 && r < 0xDC00:
+			low, err := u.readUnit()
+			if err != nil {
+				return n, err
+			}
+			r = utf16.DecodeRune(r, rune(low))
+		case utf16.IsSurrogate(r):
+			r = utf8.RuneError
+		}
+		u.pending = utf8.AppendRune(u.pending[:0], r)
+	}
+	return n, nil
+}
@@ -0,0 +1,113 @@
+package parser
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCSVReaderEncodings(t *testing.T) {
+	row := "PL,ALBPPLP1BMW,BIC11,BANK SPÓŁDZIELCZY,,KRAKÓW,POLAND,Europe/Warsaw\n"
+	latin := "DE,DEUTDEFFXXX,BIC11,DEUTSCHE BANK,,MÜNCHEN,GERMANY,Europe/Berlin\n"
+
+	tests := []struct {
+		name     string
+		input    []byte
+		params   ParseParams
+		wantName string
+		wantTown string
+	}{
+		{
+			name:     "utf-8 with BOM",
+			input:    append([]byte("\xef\xbb\xbf"), testHeader+row...),
+			wantName: "BANK SPÓŁDZIELCZY",
+			wantTown: "KRAKÓW",
+		},
+		{
+			name:     "utf-16 little endian with BOM",
+			input:    encodeUTF16LE("\ufeff" + testHeader + row),
+			wantName: "BANK SPÓŁDZIELCZY",
+			wantTown: "KRAKÓW",
+		},
+		{
+			name:     "windows-1252 detected",
+			input:    []byte(testHeader + strings.Replace(latin, "Ü", "\xdc", 1) + "DE,DEUTDEFF500,BIC11,DEUTSCHE BANK \x80,,BERLIN,GERMANY,Europe/Berlin\n"),
+			wantName: "DEUTSCHE BANK",
+			wantTown: "MÜNCHEN",
+		},
+		{
+			name:     "configured latin-1",
+			input:    []byte(testHeader + strings.Replace(latin, "Ü", "\xdc", 1)),
+			params:   ParseParams{Encoding: "latin1"},
+			wantName: "DEUTSCHE BANK",
+			wantTown: "MÜNCHEN",
+		},
+		{
+			name:     "semicolon delimiter",
+			input:    []byte(strings.ReplaceAll(testHeader, ",", ";") + "PL;ALBPPLP1BMW;BIC11;\"BANK, SA\";;KRAKÓW;POLAND;Europe/Warsaw\n"),
+			wantName: "BANK, SA",
+			wantTown: "KRAKÓW",
+		},
+		{
+			name:     "tab delimiter",
+			input:    []byte(strings.ReplaceAll(testHeader+row, ",", "\t")),
+			wantName: "BANK SPÓŁDZIELCZY",
+			wantTown: "KRAKÓW",
+		},
+		{
+			name:     "configured delimiter",
+			input:    []byte("COUNTRY ISO2 CODE|SWIFT CODE|NAME|TOWN NAME\nPL|ALBPPLP1BMW|BANK, SA|KRAKÓW\n"),
+			params:   ParseParams{Delimiter: '|'},
+			wantName: "BANK, SA",
+			wantTown: "KRAKÓW",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseCSVReader(bytes.NewReader(tt.input), tt.params)
+			require.NoError(t, err)
+			require.NotEmpty(t, result.Rows)
+			require.Equal(t, tt.wantName, result.Rows[0].Name)
+			require.Equal(t, tt.wantTown, result.Rows[0].Town)
+		})
+	}
+}
+
+func TestDecodeInputWindows1252(t *testing.T) {
+	decoded, err := decodeInput(bytes.NewReader([]byte("\x80 \x93quoted\x94 caf\xe9")), EncodingAuto)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	_, err = out.ReadFrom(decoded)
+	require.NoError(t, err)
+	require.Equal(t, "€ “quoted” café", out.String())
+
+	_, err = decodeInput(bytes.NewReader(nil), "ebcdic")
+	require.Error(t, err)
+}
+
+func TestDecodeInputChecksWholeInput(t *testing.T) {
+	padding := strings.Repeat("PL,ALBPPLP1BMW,BIC11,BANK,,KRAKOW,POLAND,Europe/Warsaw\n", 2000)
+	require.Greater(t, len(padding), 64<<10)
+	latin := "DE,DEUTDEFFXXX,BIC11,DEUTSCHE BANK,,M\xdcNCHEN,GERMANY,Europe/Berlin\n"
+
+	result, err := ParseCSVReader(strings.NewReader(testHeader+padding+latin), ParseParams{})
+	require.NoError(t, err)
+	require.Equal(t, "MÜNCHEN", result.Rows[len(result.Rows)-1].Town)
+
+	mixed := strings.Replace(padding, "KRAKOW", "KRAKÓW", 1)
+	_, err = ParseCSVReader(strings.NewReader(testHeader+mixed+latin), ParseParams{})
+	require.ErrorIs(t, err, ErrMixedEncoding)
+}
+
+func encodeUTF16LE(s string) []byte {
+	var out []byte
+	for _, unit := range utf16.Encode([]rune(s)) {
+		out = append(out, byte(unit), byte(unit>>8))
+	}
+	return out
+}
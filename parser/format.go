@@ -0,0 +1,108 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatXML    Format = "xml"
+	FormatJSON   Format = "json"
+	FormatNDJSON Format = "ndjson"
+)
+
+const sniffLen = 512
+
+var extensionFormats = map[string]Format{
+	".csv":    FormatCSV,
+	".xml":    FormatXML,
+	".json":   FormatJSON,
+	".ndjson": FormatNDJSON,
+	".jsonl":  FormatNDJSON,
+}
+
+func ParseFile(path string, params ParseParams) (*ParseResult, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if isZip(path, file) {
+		info, err := file.Stat()
+		if err != nil {
+			return nil, err
+		}
+		return ParseZip(file, info.Size(), params)
+	}
+
+	return ParseReader(file, filepath.Base(path), params)
+}
+
+// ParseReader picks the format from the file name extension, falling back
+// to the content when the name is empty or has an unknown extension.
+// Gzip-compressed input is decompressed on the fly.
+func ParseReader(r io.Reader, name string, params ParseParams) (*ParseResult, error) {
+	compressed := bufio.NewReader(r)
+	magic, err := compressed.Peek(len(zipMagic))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	switch {
+	case isGzipName(name) || bytes.HasPrefix(magic, gzipMagic):
+		return parseGzip(compressed, name, params)
+	case bytes.HasPrefix(magic, zipMagic):
+		return nil, fmt.Errorf("zip archives can only be imported from a file")
+	}
+
+	decoded, err := decodeInput(compressed, params.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	params.Encoding = EncodingUTF8
+
+	buffered := bufio.NewReader(decoded)
+	head, err := buffered.Peek(sniffLen)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+
+	format := DetectFormat(name, head)
+	switch format {
+	case FormatCSV:
+		return ParseCSVReader(buffered, params)
+	case FormatXML:
+		return ParseXMLReader(buffered, params)
+	case FormatJSON:
+		return ParseJSONReader(buffered, params)
+	case FormatNDJSON:
+		return ParseNDJSONReader(buffered, params)
+	}
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+func DetectFormat(name string, head []byte) Format {
+	if format, ok := extensionFormats[strings.ToLower(filepath.Ext(name))]; ok {
+		return format
+	}
+
+	trimmed := bytes.TrimLeft(bytes.TrimPrefix(head, bomUTF8), " \t\r\n")
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return FormatXML
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		return FormatJSON
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return FormatNDJSON
+	}
+	return FormatCSV
+}
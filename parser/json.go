@@ -0,0 +1,136 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+const maxNDJSONLineBytes = 1 << 20
+
+type jsonBank struct {
+	SwiftCode   string `json:"swiftCode"`
+	BankName    string `json:"bankName"`
+	CountryISO2 string `json:"countryISO2"`
+	Address     string `json:"address"`
+	TownName    string `json:"townName"`
+	CountryName string `json:"countryName"`
+	Timezone    string `json:"timezone"`
+}
+
+func (b jsonBank) toRow(line int) CsvRow {
+	swift := strings.TrimSpace(b.SwiftCode)
+	return CsvRow{
+		ISO2:     strings.TrimSpace(b.CountryISO2),
+		Swift:    swift,
+		Type:     fmt.Sprintf("BIC%d", len(swift)),
+		Name:     b.BankName,
+		Address:  b.Address,
+		Town:     b.TownName,
+		Country:  b.CountryName,
+		Timezone: b.Timezone,
+		Line:     line,
+	}
+}
+
+func ParseJSONReader(r io.Reader, params ParseParams) (*ParseResult, error) {
+	decoded, err := decodeInput(r, params.Encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := &lineCounter{r: decoded}
+	decoder := json.NewDecoder(lines)
+
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return nil, fmt.Errorf("invalid JSON: expected an array of banks")
+	}
+
+	result := &ParseResult{}
+	for decoder.More() {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("invalid JSON at line %d: %w", lines.lineAt(decoder.InputOffset()), err)
+		}
+		line := lines.lineAt(decoder.InputOffset() - int64(len(raw)))
+		result.addJSONRecord(raw, line)
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	return finishResult(result, params)
+}
+
+func ParseNDJSONReader(r io.Reader, params ParseParams) (*ParseResult, error) {
+	decoded, err := decodeInput(r, params.Encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(decoded)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxNDJSONLineBytes)
+
+	result := &ParseResult{}
+	line := 0
+	for scanner.Scan() {
+		line++
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+		result.addJSONRecord(raw, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read line %d: %w", line+1, err)
+	}
+
+	return finishResult(result, params)
+}
+
+func (result *ParseResult) addJSONRecord(raw []byte, line int) {
+	var bank jsonBank
+	if err := json.Unmarshal(raw, &bank); err != nil {
+		result.Errors = append(result.Errors, RowError{Line: line, Reason: fmt.Sprintf("invalid object: %v", err)})
+		return
+	}
+
+	record := bank.toRow(line)
+	if rowErrs := checkRow(&record); len(rowErrs) > 0 {
+		result.Errors = append(result.Errors, rowErrs...)
+		return
+	}
+	result.Rows = append(result.Rows, record)
+}
+
+// lineCounter remembers where newlines occur in the consumed input so that
+// byte offsets reported by encoding/json can be turned into line numbers.
+type lineCounter struct {
+	r        io.Reader
+	read     int64
+	newlines []int64
+}
+
+func (c *lineCounter) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	for i, b := range p[:n] {
+		if b == '\n' {
+			c.newlines = append(c.newlines, c.read+int64(i))
+		}
+	}
+	c.read += int64(n)
+	return n, err
+}
+
+func (c *lineCounter) lineAt(offset int64) int {
+	return sort.Search(len(c.newlines), func(i int) bool { return c.newlines[i] >= offset }) + 1
+}
@@ -0,0 +1,99 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJSONReader(t *testing.T) {
+	input := `[
+  {
+    "swiftCode": "AAISALTRXXX",
+    "bankName": "UNITED BANK OF ALBANIA SH.A",
+    "countryISO2": "AL",
+    "address": "HYRJA 3 RR. DRITAN HOXHA ND. 11 TIRANA, TIRANA, 1023",
+    "townName": "TIRANA",
+    "countryName": "ALBANIA",
+    "timezone": "Europe/Tirane",
+    "isHeadquarter": true
+  },
+  {
+    "swiftCode": 42,
+    "bankName": "BROKEN"
+  },
+  {"swiftCode": "ABIEBGS1XXX", "bankName": "ABV INVESTMENTS LTD", "countryISO2": "BG"}
+]`
+
+	t.Run("lenient", func(t *testing.T) {
+		result, err := ParseJSONReader(strings.NewReader(input), ParseParams{Mode: Lenient})
+		require.NoError(t, err)
+		require.Len(t, result.Rows, 2)
+		require.Equal(t, CsvRow{
+			ISO2:     "AL",
+			Swift:    "AAISALTRXXX",
+			Type:     "BIC11",
+			Name:     "UNITED BANK OF ALBANIA SH.A",
+			Address:  "HYRJA 3 RR. DRITAN HOXHA ND. 11 TIRANA, TIRANA, 1023",
+			Town:     "TIRANA",
+			Country:  "ALBANIA",
+			Timezone: "Europe/Tirane",
+			Line:     2,
+		}, result.Rows[0])
+		require.Equal(t, 16, result.Rows[1].Line)
+		require.Len(t, result.Errors, 1)
+		require.Equal(t, 12, result.Errors[0].Line)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		_, err := ParseJSONReader(strings.NewReader(input), ParseParams{Mode: Strict})
+		var parseErr *ParseError
+		require.ErrorAs(t, err, &parseErr)
+	})
+
+	t.Run("not an array", func(t *testing.T) {
+		_, err := ParseJSONReader(strings.NewReader(`{"swiftCode": "AAISALTRXXX"}`), ParseParams{Mode: Lenient})
+		require.Error(t, err)
+	})
+
+	t.Run("truncated array", func(t *testing.T) {
+		_, err := ParseJSONReader(strings.NewReader(`[{"swiftCode": "AAISALTRXXX"`), ParseParams{Mode: Lenient})
+		require.Error(t, err)
+	})
+}
+
+func TestParseNDJSONReader(t *testing.T) {
+	input := `{"swiftCode": "AAISALTRXXX", "bankName": "UNITED BANK OF ALBANIA SH.A", "countryISO2": "AL"}
+
+{"swiftCode": "ABIEBGS1XXX", "bankName": "ABV INVESTMENTS LTD"}
+not json
+{"swiftCode": "ADCRBGS1XXX", "bankName": "ADAMANT CAPITAL PARTNERS AD", "countryISO2": "BG"}
+`
+
+	result, err := ParseNDJSONReader(strings.NewReader(input), ParseParams{Mode: Lenient})
+	require.NoError(t, err)
+
+	var swifts []string
+	for _, row := range result.Rows {
+		swifts = append(swifts, row.Swift)
+	}
+	require.Equal(t, []string{"AAISALTRXXX", "ADCRBGS1XXX"}, swifts)
+	require.Equal(t, 5, result.Rows[1].Line)
+
+	var errLines []int
+	for _, rowErr := range result.Errors {
+		errLines = append(errLines, rowErr.Line)
+	}
+	require.Equal(t, []int{3, 4}, errLines)
+}
+
+func TestParseReaderDetectsJSON(t *testing.T) {
+	result, err := ParseReader(strings.NewReader(` [{"swiftCode": "AAISALTRXXX", "bankName": "X", "countryISO2": "AL"}]`), "", ParseParams{})
+	require.NoError(t, err)
+	require.Len(t, result.Rows, 1)
+
+	result, err = ParseReader(strings.NewReader(`{"swiftCode": "AAISALTRXXX", "bankName": "X", "countryISO2": "AL"}`), "export.jsonl", ParseParams{})
+	require.NoError(t, err)
+	require.Len(t, result.Rows, 1)
+}
@@ -1,5 +1,7 @@
 package parser
 
+import "fmt"
+
 type CsvRow struct {
 	ISO2     string
 	Swift    string
@@ -9,4 +11,65 @@ type CsvRow struct {
 	Town     string
 	Country  string
 	Timezone string
+	File     string
+	Line     int
+}
+
+type ParseMode int
+
+const (
+	Strict ParseMode = iota
+	Lenient
+)
+
+type ParseParams struct {
+	Mode      ParseMode
+	Columns   ColumnMapping
+	Encoding  string
+	Delimiter rune
+}
+
+type RowError struct {
+	File   string `json:"file,omitempty"`
+	Line   int    `json:"line"`
+	Column string `json:"column,omitempty"`
+	Reason string `json:"reason"`
+}
+
+func (e RowError) Error() string {
+	location := fmt.Sprintf("line %d", e.Line)
+	if e.File != "" {
+		location = e.File + ": " + location
+	}
+	if e.Column == "" {
+		return fmt.Sprintf("%s: %s", location, e.Reason)
+	}
+	return fmt.Sprintf("%s, column %s: %s", location, e.Column, e.Reason)
+}
+
+type ParseResult struct {
+	Rows   []CsvRow
+	Errors []RowError
+	Files  []FileSummary
+}
+
+type ParseError struct {
+	Errors []RowError
+}
+
+func (e *ParseError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more row errors)", e.Errors[0].Error(), len(e.Errors)-1)
+}
+
+func ParseModeFromString(mode string) (ParseMode, error) {
+	switch mode {
+	case "strict", "":
+		return Strict, nil
+	case "lenient":
+		return Lenient, nil
+	}
+	return Strict, fmt.Errorf("unknown import mode %q", mode)
 }
@@ -1,77 +1,210 @@
 package parser
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/csv"
+	"errors"
 	"fmt"
-	"os"
+	"io"
+	"strings"
+
+	"github.com/grysj/remitly-api/country"
+	"github.com/grysj/remitly-api/timezone"
 )
 
 func ParseCSV(pathToCSV string) ([]CsvRow, error) {
+	result, err := ParseCSVWithParams(pathToCSV, ParseParams{Mode: Strict})
+	if err != nil {
+		return nil, err
+	}
+	return result.Rows, nil
+}
+
+// ParseCSVWithParams reads the file through ParseFile, so gzip and zip
+// compressed exports are unpacked the same way as on import.
+func ParseCSVWithParams(pathToCSV string, params ParseParams) (*ParseResult, error) {
+	return ParseFile(pathToCSV, params)
+}
 
-	file, err := os.Open(pathToCSV)
+// ParseCSVReader reads every row and collects per-row errors instead of
+// stopping at the first one. In strict mode a non-empty error list is
+// returned as a *ParseError together with the partial result.
+func ParseCSVReader(r io.Reader, params ParseParams) (*ParseResult, error) {
+	decoded, err := decodeInput(r, params.Encoding)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
 
-	reader := csv.NewReader(file)
+	buffered := bufio.NewReader(decoded)
+	delimiter := params.Delimiter
+	if delimiter == 0 {
+		delimiter = detectDelimiter(buffered)
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.Comma = delimiter
 
 	header, err := reader.Read()
 	if err != nil {
 		return nil, err
 	}
 
-	idxMap, err := createColumnMap(header)
+	idxMap, err := createColumnMap(header, params.Columns)
 	if err != nil {
 		return nil, err
 	}
 
-	var records []CsvRow
+	result := &ParseResult{}
 
 	for {
 		row, err := reader.Read()
-		if err != nil {
+		if err == io.EOF {
 			break
 		}
+		if err != nil {
+			var csvErr *csv.ParseError
+			if !errors.As(err, &csvErr) {
+				return nil, err
+			}
+			result.Errors = append(result.Errors, RowError{
+				Line:   csvErr.StartLine,
+				Reason: fmt.Sprintf("%v (line %d, byte %d)", csvErr.Err, csvErr.Line, csvErr.Column),
+			})
+			continue
+		}
 
-		column := CsvRow{
-			ISO2:     row[idxMap["COUNTRY ISO2 CODE"]],
-			Swift:    row[idxMap["SWIFT CODE"]],
-			Type:     row[idxMap["CODE TYPE"]],
-			Name:     row[idxMap["NAME"]],
-			Address:  row[idxMap["ADDRESS"]],
-			Town:     row[idxMap["TOWN NAME"]],
-			Country:  row[idxMap["COUNTRY NAME"]],
-			Timezone: row[idxMap["TIME ZONE"]],
+		line, _ := reader.FieldPos(0)
+		record, rowErrs := buildRow(row, idxMap, line)
+		if len(rowErrs) > 0 {
+			result.Errors = append(result.Errors, rowErrs...)
+			continue
 		}
-		records = append(records, column)
+		result.Rows = append(result.Rows, record)
 	}
 
-	return records, nil
+	return finishResult(result, params)
+}
+
+var candidateDelimiters = []rune{',', ';', '\t'}
+
+// detectDelimiter picks the candidate that occurs most often outside quotes
+// in the header line; European Excel exports use ';' instead of ','.
+func detectDelimiter(r *bufio.Reader) rune {
+	head, _ := r.Peek(r.Size())
+	if idx := bytes.IndexByte(head, '\n'); idx >= 0 {
+		head = head[:idx]
+	}
+
+	counts := make(map[rune]int)
+	inQuotes := false
+	for _, c := range string(head) {
+		if c == '"' {
+			inQuotes = !inQuotes
+			continue
+		}
+		if !inQuotes {
+			counts[c]++
+		}
+	}
 
+	best := candidateDelimiters[0]
+	for _, candidate := range candidateDelimiters[1:] {
+		if counts[candidate] > counts[best] {
+			best = candidate
+		}
+	}
+	return best
 }
 
-func getColumnIdx(header []string, columnName string) int {
+func buildRow(row []string, idxMap map[string]int, line int) (CsvRow, []RowError) {
+	value := func(column string) string {
+		idx, ok := idxMap[column]
+		if !ok || idx >= len(row) {
+			return ""
+		}
+		return row[idx]
+	}
+
+	record := CsvRow{
+		ISO2:     value(ColumnISO2),
+		Swift:    value(ColumnSwift),
+		Type:     value(ColumnType),
+		Name:     value(ColumnName),
+		Address:  value(ColumnAddress),
+		Town:     value(ColumnTown),
+		Country:  value(ColumnCountry),
+		Timezone: value(ColumnTimezone),
+		Line:     line,
+	}
+
+	return record, checkRow(&record)
+}
+
+// checkRow validates a single record and normalizes its time zone in place.
+func checkRow(record *CsvRow) []RowError {
+	values := map[string]string{
+		ColumnISO2:  record.ISO2,
+		ColumnSwift: record.Swift,
+		ColumnName:  record.Name,
+	}
+
+	var rowErrs []RowError
+	for _, column := range requiredColumns {
+		if strings.TrimSpace(values[column]) == "" {
+			rowErrs = append(rowErrs, RowError{Line: record.Line, Column: column, Reason: "value is empty"})
+		}
+	}
+	if iso2 := strings.TrimSpace(record.ISO2); iso2 != "" && len(iso2) != 2 {
+		rowErrs = append(rowErrs, RowError{Line: record.Line, Column: ColumnISO2, Reason: fmt.Sprintf("%q is not a 2-letter code", iso2)})
+	} else if _, ok := country.ByAlpha2(iso2); iso2 != "" && !ok {
+		rowErrs = append(rowErrs, RowError{Line: record.Line, Column: ColumnISO2, Reason: fmt.Sprintf("%q is not an ISO 3166-1 country code", iso2)})
+	}
+	if zone, err := timezone.Normalize(record.Timezone); err != nil {
+		rowErrs = append(rowErrs, RowError{Line: record.Line, Column: ColumnTimezone, Reason: err.Error()})
+	} else {
+		record.Timezone = zone
+	}
 
+	return rowErrs
+}
+
+func finishResult(result *ParseResult, params ParseParams) (*ParseResult, error) {
+	if params.Mode == Strict && len(result.Errors) > 0 {
+		return result, &ParseError{Errors: result.Errors}
+	}
+	return result, nil
+}
+
+func getColumnIdx(header []string, columnName string) int {
+	want := normalizeHeader(columnName)
 	for i, column := range header {
-		if columnName == column {
+		if want == normalizeHeader(column) {
 			return i
 		}
 	}
 	return -1
 }
 
-func createColumnMap(header []string) (map[string]int, error) {
-
-	requiredColumns := []string{"COUNTRY ISO2 CODE", "SWIFT CODE", "NAME", "TOWN NAME", "COUNTRY NAME", "TIME ZONE", "CODE TYPE", "ADDRESS"}
+func createColumnMap(header []string, mapping ColumnMapping) (map[string]int, error) {
 	indexMap := make(map[string]int)
 
-	for _, req := range requiredColumns {
-		idx := getColumnIdx(header, req)
-		if idx < 0 {
-			return nil, fmt.Errorf("Error: %s column not found in CSV", req)
+	for _, column := range allColumns {
+		idx := -1
+		for _, candidate := range mapping.candidates(column) {
+			if idx = getColumnIdx(header, candidate); idx >= 0 {
+				break
+			}
+		}
+		if idx >= 0 {
+			indexMap[column] = idx
+		}
+	}
+
+	for _, column := range requiredColumns {
+		if _, ok := indexMap[column]; !ok {
+			return nil, fmt.Errorf("Error: %s column not found in CSV", column)
 		}
-		indexMap[req] = idx
 	}
 
 	return indexMap, nil
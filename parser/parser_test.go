@@ -3,6 +3,7 @@ package parser
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -34,10 +35,16 @@ func TestGetColumnIdx(t *testing.T) {
 			expected:   -1,
 		},
 		{
-			name:       "case sensitive match",
+			name:       "case insensitive match",
 			header:     []string{"COUNTRY ISO2 CODE", "Swift Code", "NAME"},
 			columnName: "SWIFT CODE",
-			expected:   -1,
+			expected:   1,
+		},
+		{
+			name:       "whitespace and separators ignored",
+			header:     []string{"country_iso2_code ", " swift  code", "NAME"},
+			columnName: "SWIFT CODE",
+			expected:   1,
 		},
 	}
 
@@ -53,6 +60,7 @@ func TestCreateColumnMap(t *testing.T) {
 	tests := []struct {
 		name        string
 		header      []string
+		mapping     ColumnMapping
 		expected    map[string]int
 		expectError bool
 	}{
@@ -75,14 +83,52 @@ func TestCreateColumnMap(t *testing.T) {
 			expectError: false,
 		},
 		{
-			name: "missing required column",
+			name: "missing optional columns",
 			header: []string{
 				"COUNTRY ISO2 CODE", "SWIFT CODE", "NAME",
 				"ADDRESS", "TOWN NAME", "COUNTRY NAME",
 			},
+			expected: map[string]int{
+				"COUNTRY ISO2 CODE": 0,
+				"SWIFT CODE":        1,
+				"NAME":              2,
+				"ADDRESS":           3,
+				"TOWN NAME":         4,
+				"COUNTRY NAME":      5,
+			},
+			expectError: false,
+		},
+		{
+			name: "missing required column",
+			header: []string{
+				"COUNTRY ISO2 CODE", "CODE TYPE", "NAME",
+				"ADDRESS", "TOWN NAME", "COUNTRY NAME", "TIME ZONE",
+			},
 			expected:    nil,
 			expectError: true,
 		},
+		{
+			name:   "built-in aliases",
+			header: []string{"BIC", "Institution Name", "Country Code", "City"},
+			expected: map[string]int{
+				"SWIFT CODE":        0,
+				"NAME":              1,
+				"COUNTRY ISO2 CODE": 2,
+				"TOWN NAME":         3,
+			},
+			expectError: false,
+		},
+		{
+			name:    "user mapping wins over aliases",
+			header:  []string{"BIC", "Our BIC", "Legal Name", "Ctry"},
+			mapping: ColumnMapping{"OUR BIC": "SWIFT CODE", "LEGAL NAME": "NAME"},
+			expected: map[string]int{
+				"SWIFT CODE":        1,
+				"NAME":              2,
+				"COUNTRY ISO2 CODE": 3,
+			},
+			expectError: false,
+		},
 		{
 			name:        "empty header",
 			header:      []string{},
@@ -111,7 +157,7 @@ func TestCreateColumnMap(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := createColumnMap(tt.header)
+			result, err := createColumnMap(tt.header, tt.mapping)
 
 			if tt.expectError {
 				require.Error(t, err)
@@ -141,3 +187,118 @@ func TestParseCSV_FileErrors(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+const testHeader = "COUNTRY ISO2 CODE,SWIFT CODE,CODE TYPE,NAME,ADDRESS,TOWN NAME,COUNTRY NAME,TIME ZONE\n"
+
+func TestParseCSVReader(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		mode        ParseMode
+		wantRows    []string
+		wantErrRows []int
+		expectError bool
+	}{
+		{
+			name: "all rows valid",
+			input: testHeader +
+				"AL,AAISALTRXXX,BIC11,UNITED BANK OF ALBANIA SH.A,\"HYRJA 3, TIRANA\",TIRANA,ALBANIA,Europe/Tirane\n" +
+				"BG,ABIEBGS1XXX,BIC11,ABV INVESTMENTS LTD,,VARNA,BULGARIA,Europe/Sofia\n",
+			mode:     Strict,
+			wantRows: []string{"AAISALTRXXX", "ABIEBGS1XXX"},
+		},
+		{
+			name: "lenient skips malformed rows and keeps reading",
+			input: testHeader +
+				"AL,AAISALTRXXX,BIC11,UNITED BANK OF ALBANIA SH.A,,TIRANA,ALBANIA,Europe/Tirane\n" +
+				"BG,ABIEBGS1XXX,BIC11\n" +
+				"BG,,BIC11,NO SWIFT,,VARNA,BULGARIA,Europe/Sofia\n" +
+				"BG,ADCRBGS1XXX,BIC11,ADAMANT CAPITAL PARTNERS AD,,SOFIA,BULGARIA,Europe/Sofia\n",
+			mode:        Lenient,
+			wantRows:    []string{"AAISALTRXXX", "ADCRBGS1XXX"},
+			wantErrRows: []int{3, 4},
+		},
+		{
+			name: "strict fails on malformed row",
+			input: testHeader +
+				"AL,AAISALTRXXX,BIC11,UNITED BANK OF ALBANIA SH.A,,TIRANA,ALBANIA,Europe/Tirane\n" +
+				"BULGARIA,ABIEBGS1XXX,BIC11,ABV INVESTMENTS LTD,,VARNA,BULGARIA,Europe/Sofia\n",
+			mode:        Strict,
+			wantRows:    []string{"AAISALTRXXX"},
+			wantErrRows: []int{3},
+			expectError: true,
+		},
+		{
+			name: "unknown time zone is a row error",
+			input: testHeader +
+				"AL,AAISALTRXXX,BIC11,UNITED BANK OF ALBANIA SH.A,,TIRANA,ALBANIA,Europe/Tirana\n" +
+				"BG,ABIEBGS1XXX,BIC11,ABV INVESTMENTS LTD,,VARNA,BULGARIA,\n",
+			mode:        Lenient,
+			wantRows:    []string{"ABIEBGS1XXX"},
+			wantErrRows: []int{2},
+		},
+		{
+			name: "unknown country code is a row error",
+			input: testHeader +
+				"ZZ,AAAAZZPRXXX,BIC11,TEST BANK,,NOWHERE,NOWHERE,\n" +
+				"BG,ABIEBGS1XXX,BIC11,ABV INVESTMENTS LTD,,VARNA,BULGARIA,\n",
+			mode:        Lenient,
+			wantRows:    []string{"ABIEBGS1XXX"},
+			wantErrRows: []int{2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseCSVReader(strings.NewReader(tt.input), ParseParams{Mode: tt.mode})
+			if tt.expectError {
+				var parseErr *ParseError
+				require.ErrorAs(t, err, &parseErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.NotNil(t, result)
+
+			var swifts []string
+			for _, row := range result.Rows {
+				swifts = append(swifts, row.Swift)
+			}
+			require.Equal(t, tt.wantRows, swifts)
+
+			var errRows []int
+			for _, rowErr := range result.Errors {
+				errRows = append(errRows, rowErr.Line)
+			}
+			require.Equal(t, tt.wantErrRows, errRows)
+		})
+	}
+}
+
+func TestParseCSVReader_NormalizesTimezone(t *testing.T) {
+	input := testHeader +
+		"UA,AAAAUAUKXXX,BIC11,TEST BANK,,KYIV,UKRAINE, europe/kiev\n"
+
+	result, err := ParseCSVReader(strings.NewReader(input), ParseParams{Mode: Strict})
+	require.NoError(t, err)
+	require.Len(t, result.Rows, 1)
+	require.Equal(t, "Europe/Kyiv", result.Rows[0].Timezone)
+}
+
+func TestLoadColumnMapping(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "mapping.json")
+	require.NoError(t, os.WriteFile(tmpFile, []byte(`{"Legal Name": "name", "bic_code": "SWIFT CODE"}`), 0666))
+
+	mapping, err := LoadColumnMapping(tmpFile, "Ctry Cd=country iso2 code")
+	require.NoError(t, err)
+	require.Equal(t, ColumnMapping{
+		"LEGAL NAME": "NAME",
+		"BIC CODE":   "SWIFT CODE",
+		"CTRY CD":    "COUNTRY ISO2 CODE",
+	}, mapping)
+
+	_, err = LoadColumnMapping("", "Legal Name=LEGAL ENTITY")
+	require.Error(t, err)
+
+	_, err = LoadColumnMapping("", "Legal Name")
+	require.Error(t, err)
+}
@@ -0,0 +1,128 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type FileSummary struct {
+	File       string `json:"file"`
+	Rows       int    `json:"rows"`
+	Rejected   int    `json:"rejected"`
+	Duplicates int    `json:"duplicates"`
+}
+
+// ParseSource imports a single file, every supported file in a directory,
+// or every file matching a glob pattern. Codes defined identically in
+// several files are kept once; codes defined differently are rejected in
+// the later file.
+func ParseSource(source string, params ParseParams) (*ParseResult, error) {
+	files, err := sourceFiles(source)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 1 && files[0] == source {
+		result, err := ParseFile(source, params)
+		if result != nil {
+			result.Files = []FileSummary{{File: source, Rows: len(result.Rows), Rejected: len(result.Errors)}}
+		}
+		return result, err
+	}
+
+	fileParams := params
+	fileParams.Mode = Lenient
+
+	merged := &ParseResult{}
+	seen := make(map[string]CsvRow)
+	for _, file := range files {
+		result, err := ParseFile(file, fileParams)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+
+		summary := FileSummary{File: file, Rejected: len(result.Errors)}
+		for _, rowErr := range result.Errors {
+			rowErr.File = joinSource(file, rowErr.File)
+			merged.Errors = append(merged.Errors, rowErr)
+		}
+		for _, row := range result.Rows {
+			swift := canonicalSwift(row.Swift)
+			first, ok := seen[swift]
+			switch {
+			case !ok:
+				row.File = joinSource(file, row.File)
+				seen[swift] = row
+				merged.Rows = append(merged.Rows, row)
+				summary.Rows++
+			case sameRecord(first, row):
+				summary.Duplicates++
+			default:
+				merged.Errors = append(merged.Errors, RowError{
+					File:   joinSource(file, row.File),
+					Line:   row.Line,
+					Column: ColumnSwift,
+					Reason: fmt.Sprintf("%s conflicts with the record in %s line %d", swift, first.File, first.Line),
+				})
+				summary.Rejected++
+			}
+		}
+		merged.Files = append(merged.Files, summary)
+	}
+
+	return finishResult(merged, params)
+}
+
+func sourceFiles(source string) ([]string, error) {
+	if info, err := os.Stat(source); err == nil {
+		if !info.IsDir() {
+			return []string{source}, nil
+		}
+		return directoryFiles(source)
+	}
+
+	if !strings.ContainsAny(source, "*?[") {
+		return []string{source}, nil
+	}
+	matches, err := filepath.Glob(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", source, err)
+	}
+	var files []string
+	for _, match := range matches {
+		if info, err := os.Stat(match); err == nil && info.Mode().IsRegular() {
+			files = append(files, match)
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no files match %q", source)
+	}
+	return files, nil
+}
+
+func directoryFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || !IsSupportedFile(entry.Name()) {
+			continue
+		}
+		files = append(files, filepath.Join(dir, entry.Name()))
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("directory %s contains no supported files", dir)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func sameRecord(a, b CsvRow) bool {
+	a.File, a.Line, b.File, b.Line = "", 0, "", 0
+	a.Swift, b.Swift = canonicalSwift(a.Swift), canonicalSwift(b.Swift)
+	return a == b
+}
@@ -0,0 +1,73 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSource(t *testing.T) {
+	albania := "AL,AAISALTRXXX,BIC11,UNITED BANK OF ALBANIA SH.A,,TIRANA,ALBANIA,Europe/Tirane\n"
+	bulgaria := "BG,ABIEBGS1XXX,BIC11,ABV INVESTMENTS LTD,,VARNA,BULGARIA,Europe/Sofia\n"
+	conflicting := "BG,ABIEBGS1XXX,BIC11,ABV INVESTMENTS PLC,,SOFIA,BULGARIA,Europe/Sofia\n"
+
+	tmpDir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(tmpDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0777))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0666))
+		return path
+	}
+
+	write("regions/al.csv", testHeader+albania)
+	write("regions/bg.json", `[{"swiftCode": "ABIEBGS1XXX", "bankName": "ABV INVESTMENTS LTD", "countryISO2": "BG", "townName": "VARNA", "countryName": "BULGARIA", "timezone": "Europe/Sofia"}]`)
+	write("regions/bg_copy.csv", testHeader+bulgaria+albania)
+	write("regions/notes.txt", "not a dataset")
+	write("conflict/a.csv", testHeader+bulgaria)
+	write("conflict/b.csv", testHeader+conflicting+albania)
+	single := write("single.csv", testHeader+albania+bulgaria)
+
+	t.Run("directory deduplicates identical records", func(t *testing.T) {
+		result, err := ParseSource(filepath.Join(tmpDir, "regions"), ParseParams{})
+		require.NoError(t, err)
+		require.Len(t, result.Rows, 2)
+		require.Equal(t, []FileSummary{
+			{File: filepath.Join(tmpDir, "regions", "al.csv"), Rows: 1},
+			{File: filepath.Join(tmpDir, "regions", "bg.json"), Rows: 1},
+			{File: filepath.Join(tmpDir, "regions", "bg_copy.csv"), Duplicates: 2},
+		}, result.Files)
+		require.Equal(t, filepath.Join(tmpDir, "regions", "al.csv"), result.Rows[0].File)
+	})
+
+	t.Run("glob reports conflicting duplicates", func(t *testing.T) {
+		result, err := ParseSource(filepath.Join(tmpDir, "conflict", "*.csv"), ParseParams{Mode: Lenient})
+		require.NoError(t, err)
+		require.Len(t, result.Rows, 2)
+		require.Len(t, result.Errors, 1)
+		require.Equal(t, filepath.Join(tmpDir, "conflict", "b.csv"), result.Errors[0].File)
+		require.Equal(t, 2, result.Errors[0].Line)
+		require.Equal(t, ColumnSwift, result.Errors[0].Column)
+		require.Equal(t, 1, result.Files[1].Rejected)
+
+		_, err = ParseSource(filepath.Join(tmpDir, "conflict", "*.csv"), ParseParams{Mode: Strict})
+		var parseErr *ParseError
+		require.ErrorAs(t, err, &parseErr)
+	})
+
+	t.Run("single file", func(t *testing.T) {
+		result, err := ParseSource(single, ParseParams{})
+		require.NoError(t, err)
+		require.Len(t, result.Rows, 2)
+		require.Equal(t, []FileSummary{{File: single, Rows: 2}}, result.Files)
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		_, err := ParseSource(filepath.Join(tmpDir, "*.xml"), ParseParams{})
+		require.Error(t, err)
+
+		_, err = ParseSource(filepath.Join(tmpDir, "missing.csv"), ParseParams{})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,191 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/grysj/remitly-api/bic"
+	"github.com/grysj/remitly-api/country"
+	"github.com/grysj/remitly-api/timezone"
+)
+
+const (
+	IssueDuplicateSwift       = "duplicate_swift_code"
+	IssueInvalidSwiftLength   = "invalid_swift_length"
+	IssueInvalidSwiftChars    = "invalid_swift_characters"
+	IssueCountryMismatch      = "swift_country_mismatch"
+	IssueMissingHeadquarters  = "missing_headquarters"
+	IssueConflictingCountries = "conflicting_country_names"
+	IssueImplausibleTimezone  = "implausible_timezone"
+	IssueCountryNameMismatch  = "country_name_mismatch"
+)
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// missing headquarters rows are common in partial vendor extracts and do
+// not break lookups, so they are reported without failing validation;
+// a zone from a neighbouring country is suspicious but still a valid zone,
+// and country names are taken from the ISO 3166 registry on import anyway
+var issueSeverity = map[string]string{
+	IssueMissingHeadquarters: SeverityWarning,
+	IssueImplausibleTimezone: SeverityWarning,
+	IssueCountryNameMismatch: SeverityWarning,
+}
+
+type ValidationIssue struct {
+	Kind      string `json:"kind"`
+	Severity  string `json:"severity"`
+	SwiftCode string `json:"swiftCode,omitempty"`
+	File      string `json:"file,omitempty"`
+	Line      int    `json:"line,omitempty"`
+	Message   string `json:"message"`
+}
+
+type ValidationReport struct {
+	Rows   int               `json:"rows"`
+	Valid  bool              `json:"valid"`
+	Issues []ValidationIssue `json:"issues"`
+}
+
+func (r *ValidationReport) add(kind string, row CsvRow, format string, args ...any) {
+	r.Issues = append(r.Issues, ValidationIssue{
+		Kind:      kind,
+		Severity:  severityOf(kind),
+		SwiftCode: row.Swift,
+		File:      row.File,
+		Line:      row.Line,
+		Message:   fmt.Sprintf(format, args...),
+	})
+}
+
+func severityOf(kind string) string {
+	if severity, ok := issueSeverity[kind]; ok {
+		return severity
+	}
+	return SeverityError
+}
+
+func (r ValidationReport) CountByKind() map[string]int {
+	counts := make(map[string]int)
+	for _, issue := range r.Issues {
+		counts[issue.Kind]++
+	}
+	return counts
+}
+
+// Validate checks a parsed dataset for consistency problems that are not
+// visible on a single row, without touching the store.
+func Validate(rows []CsvRow) ValidationReport {
+	report := ValidationReport{
+		Rows:   len(rows),
+		Issues: make([]ValidationIssue, 0),
+	}
+
+	firstSeen := make(map[string]CsvRow)
+	countryNames := make(map[string]map[string]bool)
+
+	for _, row := range rows {
+		swift := strings.ToUpper(strings.TrimSpace(row.Swift))
+		iso2 := strings.ToUpper(strings.TrimSpace(row.ISO2))
+
+		if first, ok := firstSeen[canonicalSwift(swift)]; ok {
+			report.add(IssueDuplicateSwift, row, "SWIFT code already defined on line %d", first.Line)
+		} else {
+			firstSeen[canonicalSwift(swift)] = row
+		}
+
+		code, err := bic.Parse(swift)
+		switch {
+		case errors.Is(err, bic.ErrInvalidLength):
+			report.add(IssueInvalidSwiftLength, row, "SWIFT code has %d characters, expected 8 or 11", len(swift))
+			if !isAlphanumeric(swift) {
+				report.add(IssueInvalidSwiftChars, row, "SWIFT code contains non-alphanumeric characters")
+			}
+		case errors.Is(err, bic.ErrInvalidCharacters):
+			report.add(IssueInvalidSwiftChars, row, "SWIFT code contains invalid characters")
+		}
+		if err == nil && !code.MatchesCountry(iso2) {
+			report.add(IssueCountryMismatch, row, "SWIFT country %s does not match COUNTRY ISO2 CODE %s", code.Country, iso2)
+		}
+
+		if row.Timezone != "" && !timezone.PlausibleFor(row.Timezone, iso2) {
+			report.add(IssueImplausibleTimezone, row, "time zone %s is not used in %s", row.Timezone, iso2)
+		}
+
+		countryName := strings.ToUpper(strings.TrimSpace(row.Country))
+		if registered, ok := country.ByAlpha2(iso2); ok && countryName != "" && !registered.Matches(countryName) {
+			report.add(IssueCountryNameMismatch, row, "country name %s does not match %s (%s)", countryName, iso2, registered.DisplayName())
+		}
+		if iso2 != "" && countryName != "" {
+			if countryNames[iso2] == nil {
+				countryNames[iso2] = make(map[string]bool)
+			}
+			countryNames[iso2][countryName] = true
+		}
+	}
+
+	for _, row := range rows {
+		code, err := bic.Parse(row.Swift)
+		if err != nil || code.IsHeadquarters() {
+			continue
+		}
+		hq := code.Headquarters().String()
+		if _, ok := firstSeen[hq]; !ok {
+			report.add(IssueMissingHeadquarters, row, "branch has no headquarters record %s", hq)
+		}
+	}
+
+	iso2Codes := make([]string, 0, len(countryNames))
+	for iso2 := range countryNames {
+		iso2Codes = append(iso2Codes, iso2)
+	}
+	sort.Strings(iso2Codes)
+	for _, iso2 := range iso2Codes {
+		if len(countryNames[iso2]) < 2 {
+			continue
+		}
+		names := make([]string, 0, len(countryNames[iso2]))
+		for name := range countryNames[iso2] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		report.Issues = append(report.Issues, ValidationIssue{
+			Kind:     IssueConflictingCountries,
+			Severity: severityOf(IssueConflictingCountries),
+			Message:  fmt.Sprintf("%s is mapped to several country names: %s", iso2, strings.Join(names, ", ")),
+		})
+	}
+
+	report.Valid = true
+	for _, issue := range report.Issues {
+		if issue.Severity == SeverityError {
+			report.Valid = false
+			break
+		}
+	}
+	return report
+}
+
+// canonicalSwift maps an 8-character code onto its XXX form so both
+// spellings of a headquarters are treated as the same code.
+func canonicalSwift(swift string) string {
+	swift = strings.ToUpper(strings.TrimSpace(swift))
+	if code, err := bic.Parse(swift); err == nil {
+		return code.BIC11()
+	}
+	return swift
+}
+
+func isAlphanumeric(s string) bool {
+	for _, c := range s {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
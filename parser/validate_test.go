@@ -0,0 +1,107 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		rows      []CsvRow
+		wantKinds []string
+		wantValid bool
+	}{
+		{
+			name: "consistent dataset",
+			rows: []CsvRow{
+				{ISO2: "AL", Swift: "AAISALTRXXX", Country: "ALBANIA", Line: 2},
+				{ISO2: "AL", Swift: "AAISALTR001", Country: "ALBANIA", Line: 3},
+				{ISO2: "AL", Swift: "BBBBALTR", Country: "ALBANIA", Line: 4},
+			},
+			wantKinds: nil,
+			wantValid: true,
+		},
+		{
+			name: "duplicate code",
+			rows: []CsvRow{
+				{ISO2: "AL", Swift: "AAISALTRXXX", Country: "ALBANIA", Line: 2},
+				{ISO2: "AL", Swift: "aaisaltrxxx", Country: "ALBANIA", Line: 3},
+			},
+			wantKinds: []string{IssueDuplicateSwift},
+		},
+		{
+			name: "eight-character code duplicates its XXX form",
+			rows: []CsvRow{
+				{ISO2: "AL", Swift: "AAISALTRXXX", Country: "ALBANIA", Line: 2},
+				{ISO2: "AL", Swift: "AAISALTR", Country: "ALBANIA", Line: 3},
+			},
+			wantKinds: []string{IssueDuplicateSwift},
+		},
+		{
+			name: "malformed codes",
+			rows: []CsvRow{
+				{ISO2: "AL", Swift: "AAISALTRXX", Country: "ALBANIA", Line: 2},
+				{ISO2: "AL", Swift: "AAIS-ALTXXX", Country: "ALBANIA", Line: 3},
+			},
+			wantKinds: []string{IssueInvalidSwiftLength, IssueInvalidSwiftChars},
+		},
+		{
+			name: "country letters disagree with ISO2",
+			rows: []CsvRow{
+				{ISO2: "BG", Swift: "AAISALTRXXX", Country: "BULGARIA", Line: 2},
+			},
+			wantKinds: []string{IssueCountryMismatch},
+		},
+		{
+			name: "branch without headquarters is a warning",
+			rows: []CsvRow{
+				{ISO2: "PL", Swift: "ALBPPLP1BMW", Country: "POLAND", Line: 2},
+			},
+			wantKinds: []string{IssueMissingHeadquarters},
+			wantValid: true,
+		},
+		{
+			name: "one ISO2 code with several names",
+			rows: []CsvRow{
+				{ISO2: "PL", Swift: "ALBPPLP1XXX", Country: "POLAND", Line: 2},
+				{ISO2: "PL", Swift: "BREXPLPWXXX", Country: "POLSKA", Line: 3},
+			},
+			wantKinds: []string{IssueCountryNameMismatch, IssueConflictingCountries},
+		},
+		{
+			name: "country name disagrees with the registry",
+			rows: []CsvRow{
+				{ISO2: "MT", Swift: "AKBKMTMTXXX", Country: "MALTA", Line: 2},
+				{ISO2: "MC", Swift: "BAERMCMCXXX", Country: "MALTA", Line: 3},
+				{ISO2: "PL", Swift: "ALBPPLPWXXX", Country: "Republic of Poland", Line: 4},
+			},
+			wantKinds: []string{IssueCountryNameMismatch},
+			wantValid: true,
+		},
+		{
+			name: "time zone from another country is a warning",
+			rows: []CsvRow{
+				{ISO2: "CL", Swift: "BCHICLRMXXX", Country: "CHILE", Timezone: "Pacific/Easter", Line: 2},
+				{ISO2: "PL", Swift: "ALBPPLPWXXX", Country: "POLAND", Timezone: "Europe/Berlin", Line: 3},
+			},
+			wantKinds: []string{IssueImplausibleTimezone},
+			wantValid: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Validate(tt.rows)
+
+			var kinds []string
+			for _, issue := range report.Issues {
+				kinds = append(kinds, issue.Kind)
+			}
+			require.Equal(t, tt.wantKinds, kinds)
+			require.Equal(t, tt.wantValid, report.Valid)
+			require.Equal(t, len(tt.rows), report.Rows)
+		})
+	}
+}
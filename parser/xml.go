@@ -0,0 +1,120 @@
+package parser
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ISO 20022 messages and BIC directory extracts describe an institution in
+// a FinInstnId block; the surrounding envelope differs between vendors so
+// only the blocks themselves are decoded.
+const xmlRecordElement = "FinInstnId"
+
+type xmlFinancialInstitution struct {
+	BICFI   string           `xml:"BICFI"`
+	Name    string           `xml:"Nm"`
+	Address xmlPostalAddress `xml:"PstlAdr"`
+}
+
+type xmlPostalAddress struct {
+	Street      string   `xml:"StrtNm"`
+	Building    string   `xml:"BldgNb"`
+	PostCode    string   `xml:"PstCd"`
+	Town        string   `xml:"TwnNm"`
+	Subdivision string   `xml:"CtrySubDvsn"`
+	Country     string   `xml:"Ctry"`
+	Lines       []string `xml:"AdrLine"`
+}
+
+func ParseXMLReader(r io.Reader, params ParseParams) (*ParseResult, error) {
+	decoded, err := decodeInput(r, params.Encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := xml.NewDecoder(decoded)
+	// decodeInput has already produced UTF-8, so a declared charset only
+	// needs to be one we know how to read.
+	decoder.CharsetReader = func(label string, input io.Reader) (io.Reader, error) {
+		if _, err := NormalizeEncoding(label); err != nil {
+			return nil, err
+		}
+		return input, nil
+	}
+	result := &ParseResult{}
+	found := false
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			line, _ := decoder.InputPos()
+			if !found {
+				return nil, fmt.Errorf("invalid XML: %w", err)
+			}
+			result.Errors = append(result.Errors, RowError{Line: line, Reason: fmt.Sprintf("invalid XML: %v", err)})
+			break
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != xmlRecordElement {
+			continue
+		}
+		found = true
+
+		line, _ := decoder.InputPos()
+		var institution xmlFinancialInstitution
+		if err := decoder.DecodeElement(&institution, &start); err != nil {
+			result.Errors = append(result.Errors, RowError{Line: line, Reason: fmt.Sprintf("invalid %s element: %v", xmlRecordElement, err)})
+			break
+		}
+
+		record := institution.toRow(line)
+		if rowErrs := checkRow(&record); len(rowErrs) > 0 {
+			result.Errors = append(result.Errors, rowErrs...)
+			continue
+		}
+		result.Rows = append(result.Rows, record)
+	}
+
+	if !found {
+		return nil, fmt.Errorf("no %s elements found in XML", xmlRecordElement)
+	}
+
+	return finishResult(result, params)
+}
+
+func (f xmlFinancialInstitution) toRow(line int) CsvRow {
+	swift := strings.TrimSpace(f.BICFI)
+	return CsvRow{
+		ISO2:    strings.TrimSpace(f.Address.Country),
+		Swift:   swift,
+		Type:    fmt.Sprintf("BIC%d", len(swift)),
+		Name:    strings.TrimSpace(f.Name),
+		Address: f.Address.format(),
+		Town:    strings.TrimSpace(f.Address.Town),
+		Line:    line,
+	}
+}
+
+func (a xmlPostalAddress) format() string {
+	if len(a.Lines) > 0 {
+		return joinNonEmpty(", ", a.Lines...)
+	}
+	street := joinNonEmpty(" ", a.Street, a.Building)
+	return joinNonEmpty(", ", street, a.Town, a.Subdivision, a.PostCode)
+}
+
+func joinNonEmpty(sep string, parts ...string) string {
+	kept := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, sep)
+}
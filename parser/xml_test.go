@@ -0,0 +1,97 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testBICDirectory = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:reda.bic.001">
+  <BICDir>
+    <Inst>
+      <FinInstnId>
+        <BICFI>AAISALTRXXX</BICFI>
+        <Nm>UNITED BANK OF ALBANIA SH.A</Nm>
+        <PstlAdr>
+          <StrtNm>HYRJA 3 RR. DRITAN HOXHA</StrtNm>
+          <BldgNb>11</BldgNb>
+          <PstCd>1023</PstCd>
+          <TwnNm>TIRANA</TwnNm>
+          <Ctry>AL</Ctry>
+        </PstlAdr>
+      </FinInstnId>
+    </Inst>
+    <Inst>
+      <FinInstnId>
+        <BICFI>BAERMCMC</BICFI>
+        <Nm>BANK JULIUS BAER (MONACO) S.A.M.</Nm>
+        <PstlAdr>
+          <Ctry>MC</Ctry>
+          <AdrLine>12 BOULEVARD DES MOULINS</AdrLine>
+          <AdrLine>98000 MONACO</AdrLine>
+        </PstlAdr>
+      </FinInstnId>
+    </Inst>
+    <Inst>
+      <FinInstnId>
+        <Nm>NO BIC</Nm>
+        <PstlAdr><Ctry>MC</Ctry></PstlAdr>
+      </FinInstnId>
+    </Inst>
+  </BICDir>
+</Document>`
+
+func TestParseXMLReader(t *testing.T) {
+	t.Run("lenient maps institutions to rows", func(t *testing.T) {
+		result, err := ParseXMLReader(strings.NewReader(testBICDirectory), ParseParams{Mode: Lenient})
+		require.NoError(t, err)
+		require.Len(t, result.Rows, 2)
+		require.Len(t, result.Errors, 1)
+		require.Equal(t, "SWIFT CODE", result.Errors[0].Column)
+
+		require.Equal(t, CsvRow{
+			ISO2:    "AL",
+			Swift:   "AAISALTRXXX",
+			Type:    "BIC11",
+			Name:    "UNITED BANK OF ALBANIA SH.A",
+			Address: "HYRJA 3 RR. DRITAN HOXHA 11, TIRANA, 1023",
+			Town:    "TIRANA",
+			Line:    result.Rows[0].Line,
+		}, result.Rows[0])
+		require.Equal(t, "12 BOULEVARD DES MOULINS, 98000 MONACO", result.Rows[1].Address)
+		require.Equal(t, "BIC8", result.Rows[1].Type)
+	})
+
+	t.Run("strict fails on invalid institution", func(t *testing.T) {
+		_, err := ParseXMLReader(strings.NewReader(testBICDirectory), ParseParams{Mode: Strict})
+		var parseErr *ParseError
+		require.ErrorAs(t, err, &parseErr)
+	})
+
+	t.Run("no institutions", func(t *testing.T) {
+		_, err := ParseXMLReader(strings.NewReader("<Document></Document>"), ParseParams{Mode: Lenient})
+		require.Error(t, err)
+	})
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileName string
+		head     string
+		expected Format
+	}{
+		{name: "csv extension", fileName: "SWIFT_CODES.csv", head: "<", expected: FormatCSV},
+		{name: "xml extension", fileName: "bic.XML", head: "", expected: FormatXML},
+		{name: "xml content", fileName: "export.dat", head: "\xef\xbb\xbf  <?xml", expected: FormatXML},
+		{name: "csv content", fileName: "", head: "COUNTRY ISO2 CODE,SWIFT CODE", expected: FormatCSV},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, DetectFormat(tt.fileName, []byte(tt.head)))
+		})
+	}
+}
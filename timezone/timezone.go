@@ -0,0 +1,99 @@
+package timezone
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	// the runtime image has no zoneinfo, so the database is linked into the binary
+	_ "time/tzdata"
+)
+
+//go:embed zone.tab
+var zoneTab []byte
+
+//go:embed links.tab
+var linksTab []byte
+
+var (
+	countryZones  = make(map[string][]string)
+	zoneCountries = make(map[string]map[string]bool)
+	links         = make(map[string]string)
+	// lowercase name to its spelling in the database
+	knownNames = make(map[string]string)
+)
+
+func init() {
+	eachRecord(zoneTab, func(fields []string) {
+		iso2 := fields[0]
+		for _, zone := range strings.Split(fields[1], ",") {
+			countryZones[iso2] = append(countryZones[iso2], zone)
+			if zoneCountries[zone] == nil {
+				zoneCountries[zone] = make(map[string]bool)
+			}
+			zoneCountries[zone][iso2] = true
+			knownNames[strings.ToLower(zone)] = zone
+		}
+	})
+	eachRecord(linksTab, func(fields []string) {
+		links[fields[0]] = fields[1]
+		knownNames[strings.ToLower(fields[0])] = fields[0]
+	})
+}
+
+func eachRecord(data []byte, fn func(fields []string)) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if fields := strings.Split(line, "\t"); len(fields) >= 2 {
+			fn(fields)
+		}
+	}
+}
+
+// Normalize checks name against the IANA database and returns its canonical
+// spelling, resolving deprecated link names such as Europe/Kiev. An empty
+// name is returned as is.
+func Normalize(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", nil
+	}
+	if known, ok := knownNames[strings.ToLower(name)]; ok {
+		name = known
+	}
+	if _, err := time.LoadLocation(name); err != nil || name == "Local" {
+		return "", fmt.Errorf("unknown time zone %q", name)
+	}
+	// zones listed in zone.tab are canonical even when the database links them
+	if _, ok := zoneCountries[name]; !ok {
+		if target, ok := links[name]; ok {
+			name = target
+		}
+	}
+	return name, nil
+}
+
+// PlausibleFor reports whether a canonical zone is used in the country.
+// Countries or zones missing from zone.tab, such as UTC, are not judged.
+func PlausibleFor(zone, iso2 string) bool {
+	iso2 = strings.ToUpper(strings.TrimSpace(iso2))
+	countries, ok := zoneCountries[zone]
+	if !ok || len(countryZones[iso2]) == 0 {
+		return true
+	}
+	return countries[iso2]
+}
+
+func CountryZones(iso2 string) []string {
+	zones := append([]string(nil), countryZones[strings.ToUpper(iso2)]...)
+	sort.Strings(zones)
+	return zones
+}
@@ -0,0 +1,48 @@
+package timezone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{name: "canonical", input: "Europe/Warsaw", expected: "Europe/Warsaw"},
+		{name: "deprecated alias", input: "Europe/Kiev", expected: "Europe/Kyiv"},
+		{name: "legacy US name", input: "US/Eastern", expected: "America/New_York"},
+		{name: "case and spacing", input: "  europe/warsaw ", expected: "Europe/Warsaw"},
+		{name: "zone.tab entry kept", input: "Europe/Busingen", expected: "Europe/Busingen"},
+		{name: "utc", input: "UTC", expected: "Etc/UTC"},
+		{name: "empty", input: "", expected: ""},
+		{name: "unknown", input: "Europe/Atlantis", wantErr: true},
+		{name: "local", input: "Local", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zone, err := Normalize(tt.input)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, zone)
+		})
+	}
+}
+
+func TestPlausibleFor(t *testing.T) {
+	assert.True(t, PlausibleFor("Europe/Warsaw", "PL"))
+	assert.True(t, PlausibleFor("Pacific/Easter", "cl"))
+	assert.False(t, PlausibleFor("Europe/Warsaw", "CL"))
+	assert.True(t, PlausibleFor("Etc/UTC", "PL"))
+	assert.True(t, PlausibleFor("Europe/Warsaw", "XX"))
+	assert.Contains(t, CountryZones("CL"), "America/Santiago")
+}